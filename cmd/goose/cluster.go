@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
+)
+
+const (
+	clusterReadFinal      = "final"
+	clusterReadSequential = "sequential"
+)
+
+// clusterOption returns the provider option that configures the ClickHouse cluster in opts, read
+// with the mode selected with -cluster-read, or nil if neither is set.
+func clusterOption(dialect database.Dialect, read string, opts database.ClickHouseOptions) (goose.ProviderOption, error) {
+	switch read {
+	case "", clusterReadFinal, clusterReadSequential:
+	default:
+		return nil, fmt.Errorf("invalid -cluster-read %q, must be one of: final, sequential", read)
+	}
+	if opts.Cluster == "" && (opts.ZooKeeperPath != "" || opts.ReplicaName != "") {
+		return nil, errors.New("-cluster-zookeeper-path and -cluster-replica require -cluster")
+	}
+	if opts.Cluster == "" && read == "" {
+		return nil, nil
+	}
+	if dialect != database.DialectClickHouse {
+		return nil, fmt.Errorf("-cluster and -cluster-read are not supported by the %s dialect", dialect)
+	}
+	opts.Final = read == clusterReadFinal
+	opts.SequentialConsistency = read == clusterReadSequential
+	return goose.WithClickHouse(opts), nil
+}
+
+// clusterConfig returns the ClickHouse cluster set by the global flags.
+func clusterConfig() database.ClickHouseOptions {
+	return database.ClickHouseOptions{
+		Cluster:       *cluster,
+		ZooKeeperPath: *zkPath,
+		ReplicaName:   *replicaName,
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/pressly/goose/v3/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterOption(t *testing.T) {
+	t.Parallel()
+
+	main := database.ClickHouseOptions{Cluster: "main"}
+	opt, err := clusterOption(database.DialectSQLite3, "", database.ClickHouseOptions{})
+	require.NoError(t, err)
+	require.Nil(t, opt)
+	opt, err = clusterOption(database.DialectClickHouse, clusterReadSequential, main)
+	require.NoError(t, err)
+	require.NotNil(t, opt)
+	opt, err = clusterOption(database.DialectClickHouse, clusterReadFinal, database.ClickHouseOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, opt)
+	opt, err = clusterOption(database.DialectClickHouse, "", database.ClickHouseOptions{
+		Cluster:       "main",
+		ZooKeeperPath: "/clickhouse/tables/{shard}/{database}/{table}",
+		ReplicaName:   "{replica}",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, opt)
+
+	_, err = clusterOption(database.DialectClickHouse, "quorum", main)
+	require.EqualError(t, err, `invalid -cluster-read "quorum", must be one of: final, sequential`)
+	_, err = clusterOption(database.DialectPostgres, "", main)
+	require.EqualError(t, err, "-cluster and -cluster-read are not supported by the postgres dialect")
+	_, err = clusterOption(database.DialectClickHouse, "", database.ClickHouseOptions{ReplicaName: "r1"})
+	require.EqualError(t, err, "-cluster-zookeeper-path and -cluster-replica require -cluster")
+}
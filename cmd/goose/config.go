@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"go.yaml.in/yaml/v3"
+)
+
+// defaultConfigFiles are looked up in the working directory, in order, when -config is not set.
+var defaultConfigFiles = []string{"goose.yaml", "goose.yml", "goose.toml"}
+
+// destructiveCommands require confirmation when run against a protected environment.
+var destructiveCommands = []string{"down", "down-to", "reset", "redo", "watch"}
+
+// fileConfig is a goose.yaml or goose.toml project configuration file.
+//
+//	default: local
+//	environments:
+//	  local:
+//	    driver: sqlite3
+//	    dbstring: ./local.db
+//	    dir: ./migrations
+//	  production:
+//	    driver: postgres
+//	    dbstring: postgres://localhost:5432/app
+//	    dir: ./migrations
+//	    timeout: 5m
+//	    protected: true
+type fileConfig struct {
+	// Default is the environment used when -e is not set.
+	Default      string                  `yaml:"default" toml:"default"`
+	Environments map[string]*environment `yaml:"environments" toml:"environments"`
+}
+
+// environment holds the settings of a named environment. Empty fields fall back to flags,
+// environment variables and defaults.
+type environment struct {
+	Driver       string `yaml:"driver" toml:"driver"`
+	DBString     string `yaml:"dbstring" toml:"dbstring"`
+	Dir          string `yaml:"dir" toml:"dir"`
+	Table        string `yaml:"table" toml:"table"`
+	TemplateDir  string `yaml:"template-dir" toml:"template-dir"`
+	AllowMissing bool   `yaml:"allow-missing" toml:"allow-missing"`
+	NoVersioning bool   `yaml:"no-versioning" toml:"no-versioning"`
+	Timeout      string `yaml:"timeout" toml:"timeout"`
+	Lock         string `yaml:"lock" toml:"lock"`
+	LockTimeout  string `yaml:"lock-timeout" toml:"lock-timeout"`
+	Cluster      string `yaml:"cluster" toml:"cluster"`
+	ClusterRead  string `yaml:"cluster-read" toml:"cluster-read"`
+	ZKPath       string `yaml:"cluster-zookeeper-path" toml:"cluster-zookeeper-path"`
+	ReplicaName  string `yaml:"cluster-replica" toml:"cluster-replica"`
+	CreateSchema bool   `yaml:"create-schema" toml:"create-schema"`
+	SchemaOwner  string `yaml:"schema-owner" toml:"schema-owner"`
+	// Protected requires confirmation, or -yes, before running destructive commands.
+	Protected bool `yaml:"protected" toml:"protected"`
+
+	name        string
+	timeout     time.Duration
+	lockTimeout time.Duration
+}
+
+// loadConfigFile reads the config file at path. If path is empty, the default config files are
+// looked up in the working directory and a nil config is returned if none exists.
+func loadConfigFile(path string) (*fileConfig, error) {
+	if path == "" {
+		for _, name := range defaultConfigFiles {
+			if _, err := os.Stat(name); err == nil {
+				path = name
+				break
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+		}
+		if path == "" {
+			return nil, nil
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := parseConfig(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func parseConfig(path string, data []byte) (*fileConfig, error) {
+	cfg := new(fileConfig)
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+	case ".toml":
+		md, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return nil, err
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return nil, fmt.Errorf("unknown field %q", undecoded[0].String())
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q, must be .yaml, .yml or .toml", ext)
+	}
+	if cfg.Default != "" && cfg.Environments[cfg.Default] == nil {
+		return nil, fmt.Errorf("default environment %q is not defined", cfg.Default)
+	}
+	for name, env := range cfg.Environments {
+		if env == nil {
+			env = new(environment)
+			cfg.Environments[name] = env
+		}
+		env.name = name
+		if err := env.validate(); err != nil {
+			return nil, fmt.Errorf("environment %q: %w", name, err)
+		}
+	}
+	return cfg, nil
+}
+
+func (e *environment) validate() error {
+	var err error
+	if e.Timeout != "" {
+		if e.timeout, err = time.ParseDuration(e.Timeout); err != nil {
+			return fmt.Errorf("invalid timeout: %w", err)
+		}
+	}
+	if e.LockTimeout != "" {
+		if e.lockTimeout, err = time.ParseDuration(e.LockTimeout); err != nil {
+			return fmt.Errorf("invalid lock-timeout: %w", err)
+		}
+	}
+	switch e.Lock {
+	case "", "none", "session", "table":
+	default:
+		return fmt.Errorf("invalid lock %q, must be one of: none, session, table", e.Lock)
+	}
+	switch e.ClusterRead {
+	case "", clusterReadFinal, clusterReadSequential:
+	default:
+		return fmt.Errorf("invalid cluster-read %q, must be one of: final, sequential", e.ClusterRead)
+	}
+	return nil
+}
+
+// selectEnvironment returns the environment with the given name, or the default environment if
+// name is empty. It returns nil if there is no config file and no environment was requested.
+func (c *fileConfig) selectEnvironment(name string) (*environment, error) {
+	if c == nil {
+		if name != "" {
+			return nil, fmt.Errorf("environment %q requested but no config file found (looked for %s)",
+				name, strings.Join(defaultConfigFiles, ", "))
+		}
+		return nil, nil
+	}
+	if name == "" {
+		name = c.Default
+	}
+	if name == "" {
+		return nil, nil
+	}
+	env, ok := c.Environments[name]
+	if !ok {
+		names := make([]string, 0, len(c.Environments))
+		for n := range c.Environments {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown environment %q, available: %s", name, strings.Join(names, ", "))
+	}
+	return env, nil
+}
+
+// apply fills in settings that were not set by a flag or an environment variable. setFlags holds
+// the names of the flags set on the command line.
+func (e *environment) apply(c *envConfig, setFlags map[string]bool) {
+	if c.driver == "" {
+		c.driver = e.Driver
+	}
+	if c.dbstring == "" {
+		c.dbstring = e.DBString
+	}
+	if c.table == "" {
+		c.table = e.Table
+	}
+	if c.dir == DefaultMigrationDir && e.Dir != "" {
+		c.dir = e.Dir
+	}
+	if c.templateDir == "" {
+		c.templateDir = e.TemplateDir
+	}
+	if !setFlags["allow-missing"] && e.AllowMissing {
+		*allowMissing = true
+	}
+	if !setFlags["no-versioning"] && e.NoVersioning {
+		*noVersioning = true
+	}
+	if !setFlags["timeout"] && e.timeout != 0 {
+		*timeout = e.timeout
+	}
+	if !setFlags["lock"] && e.Lock != "" {
+		*lockMode = e.Lock
+	}
+	if !setFlags["lock-timeout"] && e.lockTimeout != 0 {
+		*lockTimeout = e.lockTimeout
+	}
+	if !setFlags["cluster"] && e.Cluster != "" {
+		*cluster = e.Cluster
+	}
+	if !setFlags["cluster-read"] && e.ClusterRead != "" {
+		*clusterRead = e.ClusterRead
+	}
+	if !setFlags["cluster-zookeeper-path"] && e.ZKPath != "" {
+		*zkPath = e.ZKPath
+	}
+	if !setFlags["cluster-replica"] && e.ReplicaName != "" {
+		*replicaName = e.ReplicaName
+	}
+	if !setFlags["create-schema"] && e.CreateSchema {
+		*createSchema = true
+	}
+	if !setFlags["schema-owner"] && e.SchemaOwner != "" {
+		*schemaOwner = e.SchemaOwner
+	}
+}
+
+// confirmProtected asks for confirmation before running a destructive command against a protected
+// environment. Confirmation is skipped with -yes, and required to be given that way if stdin is not
+// a terminal.
+func confirmProtected(env *environment, command string, yes bool, in *os.File, out io.Writer) error {
+	if env == nil || !env.Protected || !slices.Contains(destructiveCommands, command) || yes {
+		return nil
+	}
+	if stat, err := in.Stat(); err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		return fmt.Errorf("environment %q is protected, use -yes to run %q non-interactively", env.name, command)
+	}
+	fmt.Fprintf(out, "Environment %q is protected. Run %q? Type the environment name to confirm: ", env.name, command)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if strings.TrimSpace(answer) != env.name {
+		return fmt.Errorf("%q against protected environment %q aborted", command, env.name)
+	}
+	return nil
+}
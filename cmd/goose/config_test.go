@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfig(t *testing.T) {
+	t.Parallel()
+
+	check := func(t *testing.T, cfg *fileConfig) {
+		t.Helper()
+		require.Equal(t, "local", cfg.Default)
+		require.Len(t, cfg.Environments, 2)
+		local := cfg.Environments["local"]
+		require.Equal(t, "sqlite3", local.Driver)
+		require.Equal(t, "./local.db", local.DBString)
+		require.False(t, local.Protected)
+		prod := cfg.Environments["production"]
+		require.Equal(t, "production", prod.name)
+		require.Equal(t, "postgres", prod.Driver)
+		require.Equal(t, "./migrations", prod.Dir)
+		require.Equal(t, "goose_versions", prod.Table)
+		require.True(t, prod.AllowMissing)
+		require.Equal(t, 5*time.Minute, prod.timeout)
+		require.Equal(t, "session", prod.Lock)
+		require.Equal(t, 30*time.Second, prod.lockTimeout)
+		require.True(t, prod.CreateSchema)
+		require.Equal(t, "app", prod.SchemaOwner)
+		require.True(t, prod.Protected)
+	}
+	t.Run("yaml", func(t *testing.T) {
+		cfg, err := parseConfig("goose.yaml", []byte(`
+default: local
+environments:
+  local:
+    driver: sqlite3
+    dbstring: ./local.db
+  production:
+    driver: postgres
+    dbstring: postgres://localhost:5432/app
+    dir: ./migrations
+    table: goose_versions
+    allow-missing: true
+    timeout: 5m
+    lock: session
+    lock-timeout: 30s
+    create-schema: true
+    schema-owner: app
+    protected: true
+`))
+		require.NoError(t, err)
+		check(t, cfg)
+	})
+	t.Run("toml", func(t *testing.T) {
+		cfg, err := parseConfig("goose.toml", []byte(`
+default = "local"
+
+[environments.local]
+driver = "sqlite3"
+dbstring = "./local.db"
+
+[environments.production]
+driver = "postgres"
+dbstring = "postgres://localhost:5432/app"
+dir = "./migrations"
+table = "goose_versions"
+allow-missing = true
+timeout = "5m"
+lock = "session"
+lock-timeout = "30s"
+create-schema = true
+schema-owner = "app"
+protected = true
+`))
+		require.NoError(t, err)
+		check(t, cfg)
+	})
+	t.Run("cluster", func(t *testing.T) {
+		cfg, err := parseConfig("goose.yaml", []byte(`
+environments:
+  analytics:
+    driver: clickhouse
+    cluster: main
+    cluster-read: sequential
+    cluster-zookeeper-path: /clickhouse/tables/{shard}/{database}/{table}
+    cluster-replica: "{replica}"
+`))
+		require.NoError(t, err)
+		env := cfg.Environments["analytics"]
+		require.Equal(t, "main", env.Cluster)
+		require.Equal(t, "/clickhouse/tables/{shard}/{database}/{table}", env.ZKPath)
+		require.Equal(t, "{replica}", env.ReplicaName)
+	})
+	t.Run("errors", func(t *testing.T) {
+		for _, tt := range []struct {
+			path, data, want string
+		}{
+			{"goose.yaml", "environments:\n  local:\n    drvier: sqlite3\n", "field drvier not found"},
+			{"goose.toml", "[environments.local]\ndrvier = \"sqlite3\"\n", `unknown field "environments.local.drvier"`},
+			{"goose.yaml", "default: staging\nenvironments:\n  local: {}\n", `default environment "staging" is not defined`},
+			{"goose.yaml", "environments:\n  local:\n    timeout: 5\n", `environment "local": invalid timeout`},
+			{"goose.yaml", "environments:\n  local:\n    lock: advisory\n", `invalid lock "advisory"`},
+			{"goose.yaml", "environments:\n  local:\n    cluster-read: quorum\n", `invalid cluster-read "quorum"`},
+			{"goose.json", "{}", `unsupported config file extension ".json"`},
+		} {
+			_, err := parseConfig(tt.path, []byte(tt.data))
+			require.Error(t, err, tt.data)
+			require.Contains(t, err.Error(), tt.want)
+		}
+	})
+}
+
+func TestSelectEnvironment(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := parseConfig("goose.yaml", []byte("default: local\nenvironments:\n  local: {}\n  staging: {}\n"))
+	require.NoError(t, err)
+
+	env, err := cfg.selectEnvironment("")
+	require.NoError(t, err)
+	require.Equal(t, "local", env.name)
+	env, err = cfg.selectEnvironment("staging")
+	require.NoError(t, err)
+	require.Equal(t, "staging", env.name)
+	_, err = cfg.selectEnvironment("prod")
+	require.EqualError(t, err, `unknown environment "prod", available: local, staging`)
+
+	var noConfig *fileConfig
+	env, err = noConfig.selectEnvironment("")
+	require.NoError(t, err)
+	require.Nil(t, env)
+	_, err = noConfig.selectEnvironment("staging")
+	require.Error(t, err)
+}
+
+func TestEnvironmentApply(t *testing.T) {
+	t.Parallel()
+
+	env := &environment{
+		Driver:   "postgres",
+		DBString: "postgres://localhost:5432/app",
+		Dir:      "./migrations",
+		Table:    "goose_versions",
+	}
+	c := &envConfig{dbstring: "from-env", dir: DefaultMigrationDir}
+	env.apply(c, map[string]bool{})
+	require.Equal(t, "postgres", c.driver)
+	require.Equal(t, "from-env", c.dbstring)
+	require.Equal(t, "./migrations", c.dir)
+	require.Equal(t, "goose_versions", c.table)
+}
+
+func TestConfirmProtected(t *testing.T) {
+	t.Parallel()
+
+	protected := &environment{name: "production", Protected: true}
+	// A regular file is not a terminal, so confirmation must be given with -yes.
+	in, err := os.Create(filepath.Join(t.TempDir(), "stdin"))
+	require.NoError(t, err)
+	t.Cleanup(func() { in.Close() })
+
+	var out bytes.Buffer
+	require.NoError(t, confirmProtected(nil, "reset", false, in, &out))
+	require.NoError(t, confirmProtected(&environment{name: "local"}, "reset", false, in, &out))
+	require.NoError(t, confirmProtected(protected, "up", false, in, &out))
+	require.NoError(t, confirmProtected(protected, "reset", true, in, &out))
+	err = confirmProtected(protected, "reset", false, in, &out)
+	require.EqualError(t, err, `environment "production" is protected, use -yes to run "reset" non-interactively`)
+	require.Empty(t, out.String())
+	// watch rolls back and re-applies a migration on every change.
+	require.Error(t, confirmProtected(protected, "watch", false, in, &out))
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pressly/goose/v3"
+)
+
+// splitDirs splits the value of -dir into its directories. The first directory is the one used by
+// commands that work on files, such as create and fix.
+func splitDirs(value string) []string {
+	var dirs []string
+	for d := range strings.SplitSeq(value, ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			dirs = append(dirs, d)
+		}
+	}
+	if len(dirs) == 0 {
+		return []string{DefaultMigrationDir}
+	}
+	return dirs
+}
+
+// additionalDirs returns the provider options that add the directories after the first one, and
+// the directory of each name, so source paths can be mapped back to the directory they were read
+// from. A directory is named after its path, or after its base name if the path is absolute or
+// outside the working directory.
+func additionalDirs(dirs []string) ([]goose.ProviderOption, map[string]string, error) {
+	var opts []goose.ProviderOption
+	names := make(map[string]string)
+	for _, dir := range dirs[1:] {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, nil, fmt.Errorf("%s directory does not exist", dir)
+		}
+		name := filepath.ToSlash(filepath.Clean(dir))
+		if !fs.ValidPath(name) {
+			name = filepath.Base(filepath.Clean(dir))
+		}
+		if existing, ok := names[name]; ok {
+			return nil, nil, fmt.Errorf("-dir %s and %s have the same name %q, use relative paths", existing, dir, name)
+		}
+		names[name] = dir
+		opts = append(opts, goose.WithAdditionalFS(name, os.DirFS(dir)))
+	}
+	return opts, names, nil
+}
+
+// sourceFile returns the path of the file of a source, which may be in an additional directory.
+func sourceFile(path, primary string, dirs map[string]string) string {
+	// Match the longest name first, so "a/b" takes precedence over "a", like the provider does.
+	names := slices.SortedFunc(maps.Keys(dirs), func(a, b string) int {
+		return cmp.Compare(len(b), len(a))
+	})
+	for _, name := range names {
+		if rest, ok := strings.CutPrefix(path, name+"/"); ok {
+			return filepath.Join(dirs[name], rest)
+		}
+	}
+	return filepath.Join(primary, path)
+}
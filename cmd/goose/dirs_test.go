@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitDirs(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"."}, splitDirs(""))
+	require.Equal(t, []string{"migrations"}, splitDirs("migrations"))
+	require.Equal(t, []string{"a", "b", "c"}, splitDirs("a, b,,c,"))
+}
+
+func TestAdditionalDirs(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	shared := filepath.Join(root, "shared")
+	require.NoError(t, os.Mkdir(shared, 0755))
+
+	opts, names, err := additionalDirs([]string{"migrations"})
+	require.NoError(t, err)
+	require.Empty(t, opts)
+	require.Empty(t, names)
+
+	// An absolute path is named after its base name.
+	opts, names, err = additionalDirs([]string{"migrations", shared})
+	require.NoError(t, err)
+	require.Len(t, opts, 1)
+	require.Equal(t, map[string]string{"shared": shared}, names)
+
+	_, _, err = additionalDirs([]string{"migrations", filepath.Join(root, "missing")})
+	require.Error(t, err)
+	_, _, err = additionalDirs([]string{"migrations", shared, shared})
+	require.Error(t, err)
+
+	var buf bytes.Buffer
+	out := newJSONOutput(&buf, "migrations", outputNDJSON)
+	out.additional = names
+	require.Equal(t,
+		jsonSource{Version: 2, Type: "sql", Path: filepath.Join(shared, "00002_b.sql")},
+		out.source(&goose.Source{Type: goose.TypeSQL, Path: "shared/00002_b.sql", Version: 2}),
+	)
+	require.Equal(t,
+		jsonSource{Version: 1, Type: "sql", Path: filepath.Join("migrations", "00001_a.sql")},
+		out.source(&goose.Source{Type: goose.TypeSQL, Path: "00001_a.sql", Version: 1}),
+	)
+}
+
+func TestSourceFile(t *testing.T) {
+	t.Parallel()
+
+	dirs := map[string]string{
+		"a":   filepath.Join("x", "a"),
+		"a/b": filepath.Join("y", "b"),
+		"c":   filepath.Join("z", "c"),
+	}
+	// Nested names resolve to the longest match, whatever the order of the map.
+	for range 20 {
+		require.Equal(t, filepath.Join("y", "b", "00002_b.sql"), sourceFile("a/b/00002_b.sql", "migrations", dirs))
+		require.Equal(t, filepath.Join("x", "a", "00001_a.sql"), sourceFile("a/00001_a.sql", "migrations", dirs))
+	}
+	require.Equal(t, filepath.Join("z", "c", "00003_c.sql"), sourceFile("c/00003_c.sql", "migrations", dirs))
+	require.Equal(t, filepath.Join("migrations", "00004_d.sql"), sourceFile("00004_d.sql", "migrations", dirs))
+}
+
+func TestGatherFilenamesDirs(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	first, second := filepath.Join(root, "first"), filepath.Join(root, "second")
+	for _, f := range []string{
+		filepath.Join(first, "00002_b.sql"),
+		filepath.Join(first, "00001_a.go"),
+		filepath.Join(first, "README.md"),
+		filepath.Join(second, "00003_c.sql"),
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(f), 0755))
+		require.NoError(t, os.WriteFile(f, nil, 0644))
+	}
+	filenames, err := gatherFilenames(first, second)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(first, "00001_a.go"),
+		filepath.Join(first, "00002_b.sql"),
+		filepath.Join(second, "00003_c.sql"),
+	}, filenames)
+}
+
+func TestRunLintDirs(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	first, second := filepath.Join(root, "first"), filepath.Join(root, "second")
+	for _, dir := range []string{first, second} {
+		require.NoError(t, os.Mkdir(dir, 0755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(first, "00001_a.sql"),
+		[]byte("-- +goose Up\nCREATE TABLE a (id int);\n-- +goose Down\nDROP TABLE a;\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(second, "00002_b.sql"),
+		[]byte("-- +goose Up\nDROP TABLE a;\n-- +goose Down\nCREATE TABLE a (id int);\n"), 0644))
+
+	// The finding in the second directory is reported as well.
+	var buf bytes.Buffer
+	err := runLint(&buf, []string{first, second}, "")
+	require.Error(t, err)
+	require.Contains(t, buf.String(), filepath.Join(second, "00002_b.sql"))
+}
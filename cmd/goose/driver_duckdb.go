@@ -0,0 +1,9 @@
+//go:build duckdb && cgo
+
+package main
+
+// The DuckDB driver requires cgo and links the DuckDB engine into the binary, so unlike the other
+// drivers it is only included when building with the duckdb tag.
+import (
+	_ "github.com/marcboeker/go-duckdb"
+)
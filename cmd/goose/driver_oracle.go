@@ -0,0 +1,7 @@
+//go:build !no_oracle
+
+package main
+
+import (
+	_ "github.com/sijms/go-ora/v2"
+)
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pressly/goose/v3/lint"
+)
+
+// runLint lints the migrations in each of dirs and writes the findings to w. It returns an error if the
+// options are invalid or any finding has error severity.
+func runLint(w io.Writer, dirs []string, driver string) error {
+	dialect := firstNonEmpty(*lintDialectFlag, driver)
+	var write func(io.Writer, []*lint.Finding) error
+	switch *lintFormat {
+	case "text":
+		write = lint.WriteText
+	case "json":
+		write = lint.WriteJSON
+	case "sarif":
+		write = lint.WriteSARIF
+	default:
+		return fmt.Errorf("unknown -lint-format %q, must be one of: text, json, sarif", *lintFormat)
+	}
+	opts := []lint.Option{
+		lint.WithDialect(dialectFromDriver(dialect)),
+	}
+	if rules := splitList(*lintDisable); len(rules) > 0 {
+		opts = append(opts, lint.WithDisabledRules(rules...))
+	}
+	if tables := splitList(*lintLargeTables); len(tables) > 0 {
+		opts = append(opts, lint.WithLargeTables(tables...))
+	}
+	var findings []*lint.Finding
+	for _, dir := range dirs {
+		dirFindings, err := lint.Lint(os.DirFS(dir), opts...)
+		if err != nil {
+			return err
+		}
+		for _, f := range dirFindings {
+			f.Path = filepath.Join(dir, f.Path)
+		}
+		findings = append(findings, dirFindings...)
+	}
+	if err := write(w, findings); err != nil {
+		return err
+	}
+	if lint.HasErrors(findings) {
+		return errors.New("found lint errors")
+	}
+	return nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
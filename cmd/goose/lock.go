@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strings"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
+	"github.com/pressly/goose/v3/lock"
+)
+
+const (
+	lockNone    = "none"
+	lockSession = "session"
+	lockTable   = "table"
+)
+
+// lockOption returns the provider option that enables the locker selected with -lock, or nil if
+// locking is disabled. A zero timeout in cfg keeps the default timeout of the locker, and a zero
+// lock ID the default lock ID.
+func lockOption(dialect database.Dialect, mode string, cfg lock.LockerConfig) (goose.ProviderOption, error) {
+	switch mode {
+	case "", lockNone:
+		return nil, nil
+	case lockSession, lockTable:
+	default:
+		return nil, fmt.Errorf("invalid -lock %q, must be one of: none, session, table", mode)
+	}
+	if cfg.LockTimeout < 0 {
+		return nil, fmt.Errorf("invalid -lock-timeout %s, must not be negative", cfg.LockTimeout)
+	}
+	if mode == lockSession {
+		locker, err := lock.NewSessionLocker(dialect, cfg)
+		if err != nil {
+			return nil, lockError(mode, dialect, err)
+		}
+		return goose.WithSessionLocker(locker), nil
+	}
+	locker, err := lock.NewLocker(dialect, cfg)
+	if err != nil {
+		return nil, lockError(mode, dialect, err)
+	}
+	return goose.WithLocker(locker), nil
+}
+
+// lockerConfig returns the locker config set by the global flags. The lock table is kept in the
+// schema of a schema-qualified version table, which is created if missing with -create-schema.
+func lockerConfig(lockID int64) lock.LockerConfig {
+	cfg := lock.LockerConfig{LockID: lockID, LockTimeout: *lockTimeout}
+	if schema, _, ok := strings.Cut(goose.TableName(), "."); ok {
+		cfg.TableName = schema + "." + lock.DefaultLockTableName
+	}
+	if *createSchema {
+		cfg.CreateSchema, cfg.SchemaOwner = true, *schemaOwner
+	}
+	return cfg
+}
+
+func lockError(mode string, dialect database.Dialect, err error) error {
+	if errors.Is(err, lock.ErrLockNotImplemented) {
+		return fmt.Errorf("-lock %s is not supported by the %s dialect", mode, dialect)
+	}
+	return err
+}
+
+// targetLockID returns the lock ID of a goose multi target, so targets on the same server do not
+// wait for each other. Like [lock.DefaultLockID], it is a crc32 checksum.
+func targetLockID(name string) int64 {
+	return int64(crc32.ChecksumIEEE([]byte("goose:" + name)))
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
+	"github.com/pressly/goose/v3/lock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLockerConfig is not parallel, since it changes the global table name and flags.
+func TestLockerConfig(t *testing.T) {
+	t.Cleanup(func() {
+		goose.SetTableName(goose.DefaultTablename)
+		*createSchema, *schemaOwner = false, ""
+	})
+
+	require.Equal(t, lock.LockerConfig{LockID: 7, LockTimeout: *lockTimeout}, lockerConfig(7))
+	// The lock table follows the schema of the version table, even if the schema is not created.
+	goose.SetTableName("migrations.goose_db_version")
+	cfg := lockerConfig(0)
+	require.Equal(t, "migrations.goose_lock", cfg.TableName)
+	require.False(t, cfg.CreateSchema)
+	*createSchema, *schemaOwner = true, "app"
+	cfg = lockerConfig(0)
+	require.Equal(t, "migrations.goose_lock", cfg.TableName)
+	require.True(t, cfg.CreateSchema)
+	require.Equal(t, "app", cfg.SchemaOwner)
+}
+
+func TestLockOption(t *testing.T) {
+	t.Parallel()
+
+	opt, err := lockOption(database.DialectSQLite3, lockNone, lock.LockerConfig{})
+	require.NoError(t, err)
+	require.Nil(t, opt)
+	opt, err = lockOption(database.DialectPostgres, lockSession, lock.LockerConfig{LockTimeout: 30 * time.Second})
+	require.NoError(t, err)
+	require.NotNil(t, opt)
+	opt, err = lockOption(database.DialectPostgres, lockTable, lock.LockerConfig{})
+	require.NoError(t, err)
+	require.NotNil(t, opt)
+	opt, err = lockOption(database.DialectPostgres, lockTable, lock.LockerConfig{
+		TableName:    "migrations.goose_lock",
+		CreateSchema: true,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, opt)
+
+	_, err = lockOption(database.DialectPostgres, "advisory", lock.LockerConfig{})
+	require.EqualError(t, err, `invalid -lock "advisory", must be one of: none, session, table`)
+	_, err = lockOption(database.DialectPostgres, lockTable, lock.LockerConfig{LockTimeout: -time.Second})
+	require.EqualError(t, err, "invalid -lock-timeout -1s, must not be negative")
+	_, err = lockOption(database.DialectSQLite3, lockSession, lock.LockerConfig{})
+	require.EqualError(t, err, "-lock session is not supported by the sqlite3 dialect")
+}
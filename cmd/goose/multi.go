@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
+)
+
+// multiTarget is a target of goose multi, read from -targets or -schemas.
+type multiTarget struct {
+	name     string
+	dbstring string
+}
+
+// runMulti runs a command on every target listed in -targets, or on every schema listed in
+// -schemas of a single database. args are the arguments after multi:
+//
+//	goose -targets FILE [OPTIONS] multi DRIVER COMMAND [VERSION]
+//	goose -schemas FILE [OPTIONS] multi DRIVER DBSTRING COMMAND [VERSION]
+func runMulti(ctx context.Context, args []string, dirs []string) error {
+	if *noVersioning {
+		return errors.New("multi does not support -no-versioning")
+	}
+	var (
+		targets []multiTarget
+		err     error
+	)
+	switch {
+	case *targetsFile != "" && *schemasFile != "":
+		return errors.New("multi takes either -targets or -schemas, not both")
+	case *targetsFile != "":
+		if len(args) < 2 {
+			return errors.New("multi must be of form: goose -targets FILE [OPTIONS] multi DRIVER COMMAND [VERSION]")
+		}
+		targets, err = readTargets(*targetsFile)
+	case *schemasFile != "":
+		if len(args) < 3 {
+			return errors.New("multi must be of form: goose -schemas FILE [OPTIONS] multi DRIVER DBSTRING COMMAND [VERSION]")
+		}
+		if dialectFromDriver(args[0]) != database.DialectPostgres {
+			return fmt.Errorf("-schemas is not supported by the %s driver, only postgres", args[0])
+		}
+		targets, err = readSchemaTargets(*schemasFile, args[1])
+		args = append(args[:1:1], args[2:]...)
+	default:
+		return errors.New("multi requires -targets or -schemas")
+	}
+	if err != nil {
+		return err
+	}
+	driver, command, arguments := args[0], args[1], args[2:]
+	versionArg := func() (int64, error) {
+		if len(arguments) == 0 {
+			return 0, fmt.Errorf("%s must be of form: goose multi ... %s VERSION", command, command)
+		}
+		version, err := strconv.ParseInt(arguments[0], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("version must be a number (got '%s')", arguments[0])
+		}
+		return version, nil
+	}
+
+	dialect := dialectFromDriver(driver)
+	opts := providerOptions()
+	dirOpts, _, err := additionalDirs(dirs)
+	if err != nil {
+		return err
+	}
+	opts = append(opts, dirOpts...)
+	clusterOpt, err := clusterOption(dialect, *clusterRead, clusterConfig())
+	if err != nil {
+		return err
+	}
+	if clusterOpt != nil {
+		opts = append(opts, clusterOpt)
+	}
+	gooseTargets, err := openTargets(driver, dialect, targets)
+	if err != nil {
+		return err
+	}
+	// NewMultiProvider owns the DBs from here on and closes them if it fails.
+	mp, err := goose.NewMultiProvider(dialect, os.DirFS(dirs[0]), gooseTargets,
+		goose.WithConcurrency(*concurrency),
+		goose.WithStopOnError(*stopOnError),
+		goose.WithProviderOptions(opts...),
+		goose.WithTargetDone(logTargetResult),
+	)
+	if err != nil {
+		if errors.Is(err, goose.ErrNoMigrations) {
+			err = goose.ErrNoMigrationFiles
+		}
+		return err
+	}
+	defer mp.Close()
+
+	var results []*goose.TargetResult
+	switch command {
+	case "up":
+		results, err = mp.Up(ctx)
+	case "up-to":
+		var version int64
+		if version, err = versionArg(); err != nil {
+			return err
+		}
+		results, err = mp.UpTo(ctx, version)
+	case "down":
+		results, err = mp.Down(ctx)
+	case "down-to":
+		var version int64
+		if version, err = versionArg(); err != nil {
+			return err
+		}
+		results, err = mp.DownTo(ctx, version)
+	default:
+		return fmt.Errorf("%q: not supported by multi, must be one of: up, up-to, down, down-to", command)
+	}
+	var multiErr *goose.MultiError
+	if errors.As(err, &multiErr) {
+		names := make([]string, 0, len(multiErr.Failed))
+		for _, r := range multiErr.Failed {
+			names = append(names, r.Target)
+		}
+		return fmt.Errorf("%d of %d targets failed: %s", len(multiErr.Failed), multiErr.Total, strings.Join(names, ", "))
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("goose: %d targets migrated", len(results))
+	return nil
+}
+
+// multiCommand returns the command in the arguments after multi, which follows the DBSTRING with
+// -schemas, or "" if it is missing.
+func multiCommand(args []string, schemas bool) string {
+	i := 1
+	if schemas {
+		i = 2
+	}
+	if len(args) <= i {
+		return ""
+	}
+	return args[i]
+}
+
+// openTargets opens the DB of every target. If a target fails, the DBs opened so far are closed.
+func openTargets(driver string, dialect database.Dialect, targets []multiTarget) (_ []goose.Target, retErr error) {
+	gooseTargets := make([]goose.Target, 0, len(targets))
+	defer func() {
+		if retErr != nil {
+			for _, t := range gooseTargets {
+				_ = t.DB.Close()
+			}
+		}
+	}()
+	for _, t := range targets {
+		db, err := goose.OpenDBWithDriver(driver, normalizeDBString(driver, t.dbstring, *certfile, *sslcert, *sslkey))
+		if err != nil {
+			return nil, fmt.Errorf("target %s: %w", t.name, err)
+		}
+		// Only a few targets are migrated at once, do not keep idle connections to all of them.
+		db.SetMaxIdleConns(0)
+		gooseTargets = append(gooseTargets, goose.Target{Name: t.name, DB: db})
+		lockOpt, err := lockOption(dialect, *lockMode, lockerConfig(targetLockID(t.name)))
+		if err != nil {
+			return nil, err
+		}
+		if lockOpt != nil {
+			gooseTargets[len(gooseTargets)-1].Options = []goose.ProviderOption{lockOpt}
+		}
+	}
+	return gooseTargets, nil
+}
+
+// logTargetResult logs one line per target as it finishes.
+func logTargetResult(res *goose.TargetResult) {
+	switch {
+	case errors.Is(res.Err, goose.ErrTargetSkipped):
+		log.Printf("%s: SKIPPED", res.Target)
+	case res.Err != nil:
+		log.Printf("%s: FAILED %v", res.Target, runError(res.Err))
+	case len(res.Results) == 0:
+		log.Printf("%s: OK   no migrations to run", res.Target)
+	default:
+		last := res.Results[len(res.Results)-1]
+		log.Printf("%s: OK   %d migrations, last %s", res.Target, len(res.Results), filepath.Base(last.Source.Path))
+	}
+}
+
+// readTargets reads the -targets file, with one NAME DBSTRING pair per line. Empty lines and
+// lines starting with # are ignored.
+func readTargets(path string) ([]multiTarget, error) {
+	lines, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]multiTarget, 0, len(lines))
+	for _, line := range lines {
+		i := strings.IndexFunc(line, unicode.IsSpace)
+		if i < 0 {
+			return nil, fmt.Errorf("-targets: invalid line %q, must be NAME DBSTRING", line)
+		}
+		targets = append(targets, multiTarget{
+			name:     line[:i],
+			dbstring: strings.TrimSpace(line[i:]),
+		})
+	}
+	return targets, nil
+}
+
+// readSchemaTargets reads the -schemas file, with one schema per line, and returns a target per
+// schema that sets the search_path of every connection to the schema.
+func readSchemaTargets(path, dbstring string) ([]multiTarget, error) {
+	schemas, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]multiTarget, 0, len(schemas))
+	for _, schema := range schemas {
+		targets = append(targets, multiTarget{name: schema, dbstring: withSearchPath(dbstring, schema)})
+	}
+	return targets, nil
+}
+
+// withSearchPath adds the search_path runtime parameter to a postgres connection string, in URL
+// or keyword/value form.
+func withSearchPath(dbstring, schema string) string {
+	if u, err := url.Parse(dbstring); err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql") {
+		q := u.Query()
+		q.Set("search_path", schema)
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+	quoted := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(schema)
+	return dbstring + " search_path='" + quoted + "'"
+}
+
+// readLines returns the trimmed lines of a file, or of stdin if path is -, skipping empty lines
+// and comments.
+func readLines(path string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("no targets in %s", path)
+	}
+	return lines, nil
+}
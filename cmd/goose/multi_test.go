@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTargets(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "targets.txt")
+	require.NoError(t, os.WriteFile(path, []byte(`
+# tenants
+a postgres://localhost/a
+b	host=localhost dbname=b
+`), 0644))
+	targets, err := readTargets(path)
+	require.NoError(t, err)
+	require.Equal(t, []multiTarget{
+		{name: "a", dbstring: "postgres://localhost/a"},
+		{name: "b", dbstring: "host=localhost dbname=b"},
+	}, targets)
+
+	require.NoError(t, os.WriteFile(path, []byte("a\n"), 0644))
+	_, err = readTargets(path)
+	require.Error(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("# nothing\n"), 0644))
+	_, err = readTargets(path)
+	require.Error(t, err)
+}
+
+func TestWithSearchPath(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t,
+		"postgres://user@localhost:5432/db?search_path=tenant_a&sslmode=disable",
+		withSearchPath("postgres://user@localhost:5432/db?sslmode=disable", "tenant_a"),
+	)
+	require.Equal(t,
+		"host=localhost dbname=db search_path='tenant_a'",
+		withSearchPath("host=localhost dbname=db", "tenant_a"),
+	)
+	require.Equal(t,
+		`host=localhost search_path='it\'s'`,
+		withSearchPath("host=localhost", "it's"),
+	)
+}
+
+func TestMultiCommand(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "down-to", multiCommand([]string{"postgres", "down-to", "3"}, false))
+	require.Equal(t, "down", multiCommand([]string{"postgres", "postgres://localhost/db", "down"}, true))
+	require.Empty(t, multiCommand([]string{"postgres"}, false))
+	require.Empty(t, multiCommand([]string{"postgres", "postgres://localhost/db"}, true))
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/internal/migrationstats"
+)
+
+const (
+	outputText   = "text"
+	outputJSON   = "json"
+	outputNDJSON = "ndjson"
+)
+
+// jsonCommands are the commands that support -output json and -output ndjson.
+var jsonCommands = []string{
+	"status", "history", "doctor", "version", "up", "up-by-one", "up-to", "down", "down-to", "redo", "reset", "validate", "env",
+}
+
+// checkOutputFormat validates the -output format and, if command is not empty, that the command
+// supports it.
+func checkOutputFormat(format, command string) error {
+	switch format {
+	case outputText:
+		return nil
+	case outputJSON, outputNDJSON:
+		if command != "" && !slices.Contains(jsonCommands, command) {
+			return fmt.Errorf("-output %s is not supported by %q", format, command)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown -output %q, must be one of: text, json, ndjson", format)
+}
+
+// jsonOutput writes command output as JSON. With -output json, list items are buffered and written
+// as a single array when flushed. With -output ndjson, every item is written as soon as it is
+// available, one compact object per line, so long runs can be followed as they progress.
+type jsonOutput struct {
+	w      io.Writer
+	dir    string
+	stream bool
+	items  []any
+
+	// additional maps the names of additional migration directories to their paths, see
+	// [additionalDirs].
+	additional map[string]string
+}
+
+var _ reporter = (*jsonOutput)(nil)
+
+func newJSONOutput(w io.Writer, dir, format string) *jsonOutput {
+	return &jsonOutput{w: w, dir: dir, stream: format == outputNDJSON}
+}
+
+// item adds an element to the output list.
+func (o *jsonOutput) item(v any) error {
+	if o.stream {
+		return json.NewEncoder(o.w).Encode(v)
+	}
+	o.items = append(o.items, v)
+	return nil
+}
+
+// object writes a single object, for commands that do not produce a list.
+func (o *jsonOutput) object(v any) error {
+	return o.encode(v)
+}
+
+// flush writes the buffered list. An empty list is written as [].
+func (o *jsonOutput) flush() error {
+	if o.stream {
+		return nil
+	}
+	if o.items == nil {
+		o.items = []any{}
+	}
+	return o.encode(o.items)
+}
+
+func (o *jsonOutput) encode(v any) error {
+	enc := json.NewEncoder(o.w)
+	if !o.stream {
+		enc.SetIndent("", "  ")
+	}
+	return enc.Encode(v)
+}
+
+type jsonSource struct {
+	Version int64  `json:"version"`
+	Type    string `json:"type"`
+	Path    string `json:"path,omitempty"`
+}
+
+type jsonStatus struct {
+	jsonSource
+	State     string     `json:"state"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+type jsonResult struct {
+	jsonSource
+	Direction  string  `json:"direction"`
+	DurationMS float64 `json:"duration_ms"`
+	Empty      bool    `json:"empty"`
+	Error      string  `json:"error,omitempty"`
+}
+
+type jsonHistory struct {
+	Version   int64      `json:"version"`
+	Type      string     `json:"type,omitempty"`
+	Path      string     `json:"path,omitempty"`
+	IsApplied bool       `json:"is_applied"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
+type jsonFinding struct {
+	Check       string `json:"check"`
+	Version     int64  `json:"version,omitempty"`
+	Message     string `json:"message"`
+	Remediation string `json:"remediation"`
+}
+
+type jsonVersion struct {
+	Version int64 `json:"version"`
+}
+
+type jsonStats struct {
+	File      string `json:"file"`
+	Version   int64  `json:"version"`
+	Tx        bool   `json:"tx"`
+	UpCount   int    `json:"up_count"`
+	DownCount int    `json:"down_count"`
+}
+
+type jsonEnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func newJSONResult(src jsonSource, r *goose.MigrationResult) jsonResult {
+	res := jsonResult{
+		jsonSource: src,
+		Direction:  r.Direction,
+		DurationMS: float64(r.Duration.Microseconds()) / 1000,
+		Empty:      r.Empty,
+	}
+	if r.Error != nil {
+		res.Error = r.Error.Error()
+	}
+	return res
+}
+
+// source returns the JSON form of s, with the path joined to the directory it was read from.
+func (o *jsonOutput) source(s *goose.Source) jsonSource {
+	src := jsonSource{Version: s.Version, Type: string(s.Type)}
+	if s.Path != "" {
+		src.Path = sourceFile(s.Path, o.dir, o.additional)
+	}
+	return src
+}
+
+// result implements reporter.
+func (o *jsonOutput) result(r *goose.MigrationResult) error {
+	return o.item(newJSONResult(o.source(r.Source), r))
+}
+
+// status implements reporter.
+func (o *jsonOutput) status(statuses []*goose.MigrationStatus) error {
+	for _, s := range statuses {
+		st := jsonStatus{jsonSource: o.source(s.Source), State: string(s.State)}
+		if !s.AppliedAt.IsZero() {
+			appliedAt := s.AppliedAt.UTC()
+			st.AppliedAt = &appliedAt
+		}
+		if err := o.item(st); err != nil {
+			return err
+		}
+	}
+	return o.flush()
+}
+
+// history implements reporter.
+func (o *jsonOutput) history(entries []*goose.HistoryEntry) error {
+	for _, e := range entries {
+		h := jsonHistory{Version: e.Version, IsApplied: e.IsApplied}
+		if e.Source != nil {
+			src := o.source(e.Source)
+			h.Type, h.Path = src.Type, src.Path
+		}
+		if !e.Timestamp.IsZero() {
+			timestamp := e.Timestamp.UTC()
+			h.Timestamp = &timestamp
+		}
+		if err := o.item(h); err != nil {
+			return err
+		}
+	}
+	return o.flush()
+}
+
+// doctor implements reporter.
+func (o *jsonOutput) doctor(findings []*goose.DoctorFinding) error {
+	for _, f := range findings {
+		if err := o.item(jsonFinding{
+			Check:       string(f.Check),
+			Version:     f.Version,
+			Message:     f.Message,
+			Remediation: f.Remediation,
+		}); err != nil {
+			return err
+		}
+	}
+	return o.flush()
+}
+
+// version implements reporter.
+func (o *jsonOutput) version(v int64) error {
+	return o.object(jsonVersion{Version: v})
+}
+
+// done implements reporter.
+func (o *jsonOutput) done(string, int64, int) error {
+	return o.flush()
+}
+
+func writeValidateJSON(out *jsonOutput, dirs []string) error {
+	filenames, err := gatherFilenames(dirs...)
+	if err != nil {
+		return err
+	}
+	stats, err := migrationstats.GatherStats(migrationstats.NewFileWalker(filenames...), false)
+	if err != nil {
+		return err
+	}
+	for _, s := range stats {
+		if err := out.item(jsonStats{
+			File:      s.FileName,
+			Version:   s.Version,
+			Tx:        s.Tx,
+			UpCount:   s.UpCount,
+			DownCount: s.DownCount,
+		}); err != nil {
+			return err
+		}
+	}
+	return out.flush()
+}
+
+func writeEnvJSON(out *jsonOutput, envs []envVar) error {
+	for _, env := range envs {
+		if err := out.item(jsonEnvVar{Name: env.Name, Value: env.Value}); err != nil {
+			return err
+		}
+	}
+	return out.flush()
+}
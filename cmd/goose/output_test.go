@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, checkOutputFormat(outputText, "create"))
+	require.NoError(t, checkOutputFormat(outputJSON, "status"))
+	require.NoError(t, checkOutputFormat(outputNDJSON, ""))
+	require.EqualError(t, checkOutputFormat(outputJSON, "create"), `-output json is not supported by "create"`)
+	require.EqualError(t, checkOutputFormat("yaml", ""), `unknown -output "yaml", must be one of: text, json, ndjson`)
+}
+
+func TestJSONOutput(t *testing.T) {
+	t.Parallel()
+
+	applied := &goose.MigrationResult{
+		Source:    &goose.Source{Type: goose.TypeSQL, Path: "00001_a.sql", Version: 1},
+		Duration:  1500 * time.Microsecond,
+		Direction: "up",
+	}
+	failed := &goose.MigrationResult{
+		Source:    &goose.Source{Type: goose.TypeSQL, Path: "00002_b.sql", Version: 2},
+		Direction: "up",
+		Error:     errors.New("boom"),
+	}
+	partialErr := &goose.PartialError{
+		Applied: []*goose.MigrationResult{applied},
+		Failed:  failed,
+		Err:     errors.New("boom"),
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		stream := &resultStream{reporter: newJSONOutput(&buf, "migrations", outputJSON)}
+		stream.migrationDone(applied)
+		stream.migrationDone(failed)
+		err := stream.finish(partialErr)
+		require.ErrorIs(t, err, partialErr)
+		require.JSONEq(t, `[
+			{"version":1,"type":"sql","path":"migrations/00001_a.sql","direction":"up","duration_ms":1.5,"empty":false},
+			{"version":2,"type":"sql","path":"migrations/00002_b.sql","direction":"up","duration_ms":0,"empty":false,"error":"boom"}
+		]`, buf.String())
+	})
+	t.Run("ndjson", func(t *testing.T) {
+		var buf bytes.Buffer
+		stream := &resultStream{reporter: newJSONOutput(&buf, "migrations", outputNDJSON)}
+		// Every result is written as soon as the migration finished.
+		stream.migrationDone(applied)
+		require.Equal(t,
+			`{"version":1,"type":"sql","path":"migrations/00001_a.sql","direction":"up","duration_ms":1.5,"empty":false}`+"\n",
+			buf.String(),
+		)
+		require.NoError(t, stream.finish(nil))
+		require.Equal(t, 1, stream.applied)
+	})
+	t.Run("empty", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, newJSONOutput(&buf, "", outputJSON).flush())
+		require.Equal(t, "[]\n", buf.String())
+		buf.Reset()
+		require.NoError(t, newJSONOutput(&buf, "", outputNDJSON).flush())
+		require.Empty(t, buf.String())
+	})
+}
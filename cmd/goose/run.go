@@ -0,0 +1,442 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
+)
+
+// reporter receives the output of database commands run by [runCommand].
+type reporter interface {
+	// result is called for every migration that was applied or rolled back.
+	result(*goose.MigrationResult) error
+	// status is called with the status of all migrations.
+	status([]*goose.MigrationStatus) error
+	// history is called with every row of the version table, oldest first.
+	history([]*goose.HistoryEntry) error
+	// doctor is called with the problems found by goose doctor, which may be none.
+	doctor([]*goose.DoctorFinding) error
+	// version is called with the current database version, or the last file version when
+	// versioning is disabled.
+	version(int64) error
+	// done is called when up, up-to or down-to finish. current is the resulting version and
+	// applied the number of migrations that ran.
+	done(command string, current int64, applied int) error
+	// flush is called after all results of a command have been reported, unless done is called.
+	flush() error
+}
+
+// textReporter writes the log lines printed by the goose package functions, so the output of the
+// CLI does not change with the Provider.
+type textReporter struct {
+	noVersioning bool
+}
+
+var _ reporter = (*textReporter)(nil)
+
+func (r *textReporter) result(res *goose.MigrationResult) error {
+	if res.Error != nil {
+		// The error of a failed migration is returned, and logged by the caller.
+		return nil
+	}
+	state := "OK  "
+	if res.Empty {
+		state = "EMPTY"
+	}
+	log.Printf("%s %s (%s)", state, filepath.Base(res.Source.Path), truncateDuration(res.Duration))
+	return nil
+}
+
+func (r *textReporter) status(statuses []*goose.MigrationStatus) error {
+	log.Printf("    Applied At                  Migration")
+	log.Printf("    =======================================")
+	for _, s := range statuses {
+		appliedAt := "Pending"
+		switch {
+		case r.noVersioning:
+			appliedAt = "no versioning"
+		case s.State == goose.StateApplied:
+			appliedAt = s.AppliedAt.Format(time.ANSIC)
+		}
+		log.Printf("    %-24s -- %v", appliedAt, filepath.Base(s.Source.Path))
+	}
+	return nil
+}
+
+func (r *textReporter) history(entries []*goose.HistoryEntry) error {
+	log.Printf("    Recorded At                 Direction  Migration")
+	log.Printf("    ===================================================")
+	for _, e := range entries {
+		recordedAt := "-"
+		if !e.Timestamp.IsZero() {
+			recordedAt = e.Timestamp.Format(time.ANSIC)
+		}
+		direction := "up"
+		if !e.IsApplied {
+			direction = "down"
+		}
+		migration := fmt.Sprintf("version %d", e.Version)
+		if e.Source != nil && e.Source.Path != "" {
+			migration = filepath.Base(e.Source.Path)
+		}
+		log.Printf("    %-24s -- %-9s  %v", recordedAt, direction, migration)
+	}
+	return nil
+}
+
+func (r *textReporter) doctor(findings []*goose.DoctorFinding) error {
+	if len(findings) == 0 {
+		log.Printf("goose: no problems found")
+		return nil
+	}
+	for _, f := range findings {
+		log.Printf("%s: %s", f.Check, f.Message)
+		log.Printf("    %s", f.Remediation)
+	}
+	return nil
+}
+
+func (r *textReporter) version(v int64) error {
+	if r.noVersioning {
+		log.Printf("goose: file version %v", v)
+	} else {
+		log.Printf("goose: version %v", v)
+	}
+	return nil
+}
+
+func (r *textReporter) done(command string, current int64, applied int) error {
+	switch {
+	case r.noVersioning && command == "down-to":
+		log.Printf("goose: down to current file version: %d", current)
+	case r.noVersioning:
+		log.Printf("goose: up to current file version: %d", current)
+	case applied == 0 || command == "down-to":
+		log.Printf("goose: no migrations to run. current version: %d", current)
+	default:
+		log.Printf("goose: successfully migrated database to version: %d", current)
+	}
+	return nil
+}
+
+func (r *textReporter) flush() error { return nil }
+
+const (
+	grayColor  = "\033[90m"
+	resetColor = "\033[00m"
+)
+
+// verboseLogger writes the -v output of the provider in gray, as the goose package functions do,
+// unless color is disabled with -no-color or NO_COLOR.
+type verboseLogger struct {
+	noColor bool
+}
+
+var _ goose.Logger = (*verboseLogger)(nil)
+
+func (l *verboseLogger) Printf(format string, v ...any) {
+	if l.noColor {
+		log.Printf(format, v...)
+	} else {
+		log.Printf(grayColor+format+resetColor, v...)
+	}
+}
+
+func (l *verboseLogger) Fatalf(format string, v ...any) {
+	log.Fatalf(format, v...)
+}
+
+// truncateDuration mirrors the precision of durations logged by the goose package.
+func truncateDuration(d time.Duration) time.Duration {
+	for _, v := range []time.Duration{
+		time.Second,
+		time.Millisecond,
+		time.Microsecond,
+	} {
+		if d > v {
+			return d.Round(v / time.Duration(100))
+		}
+	}
+	return d
+}
+
+// dialectFromDriver returns the dialect for a CLI driver name, accepting the same built-in and
+// registered driver names as [goose.OpenDBWithDriver].
+func dialectFromDriver(driver string) database.Dialect {
+	if d, _, ok := goose.LookupDriver(driver); ok {
+		return d
+	}
+	return database.Dialect(driver)
+}
+
+// runCommand runs a database command with the provider and reports its output to r. The provider
+// must report the result of every migration to r, see [resultStream].
+func runCommand(
+	ctx context.Context,
+	p *goose.Provider,
+	r *resultStream,
+	noVersioning bool,
+	command string,
+	args []string,
+) error {
+	r.applied = 0
+	versionArg := func() (int64, error) {
+		if len(args) == 0 {
+			return 0, fmt.Errorf("%s must be of form: goose DRIVER DBSTRING [OPTIONS] %s VERSION", command, command)
+		}
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("version must be a number (got '%s')", args[0])
+		}
+		return version, nil
+	}
+	if noVersioning {
+		return runNoVersioning(ctx, p, r, command, versionArg)
+	}
+	switch command {
+	case "status":
+		statuses, err := p.Status(ctx)
+		if err != nil {
+			return err
+		}
+		return r.status(statuses)
+	case "history":
+		entries, err := p.History(ctx)
+		if err != nil {
+			return err
+		}
+		return r.history(entries)
+	case "doctor":
+		findings, err := p.Doctor(ctx)
+		if err != nil {
+			return err
+		}
+		if err := r.doctor(findings); err != nil {
+			return err
+		}
+		if len(findings) > 0 {
+			return fmt.Errorf("doctor found %d problem(s)", len(findings))
+		}
+		return nil
+	case "upgrade-table":
+		res, err := p.UpgradeTable(ctx)
+		if err != nil {
+			return err
+		}
+		if res.From == res.To {
+			log.Printf("goose: version table is up to date, layout %d", res.To)
+		} else {
+			log.Printf("goose: upgraded version table from layout %d to %d", res.From, res.To)
+		}
+		return nil
+	case "version":
+		version, err := p.GetDBVersion(ctx)
+		if err != nil {
+			return err
+		}
+		return r.version(version)
+	case "up", "up-to":
+		var err error
+		if command == "up-to" {
+			version, verr := versionArg()
+			if verr != nil {
+				return verr
+			}
+			_, err = p.UpTo(ctx, version)
+		} else {
+			_, err = p.Up(ctx)
+		}
+		return reportDone(ctx, p, r, command, err)
+	case "up-by-one":
+		_, err := p.UpByOne(ctx)
+		if errors.Is(err, goose.ErrNoNextVersion) {
+			current, verr := p.GetDBVersion(ctx)
+			if verr != nil {
+				return verr
+			}
+			if derr := r.done(command, current, 0); derr != nil {
+				return derr
+			}
+			return err
+		}
+		return r.finish(err)
+	case "down":
+		_, err := p.Down(ctx)
+		if errors.Is(err, goose.ErrNoNextVersion) {
+			return fmt.Errorf("no migrations to roll back: %w", err)
+		}
+		return r.finish(err)
+	case "down-to", "reset":
+		var version int64
+		if command == "down-to" {
+			var err error
+			if version, err = versionArg(); err != nil {
+				return err
+			}
+		}
+		_, err := p.DownTo(ctx, version)
+		if command == "reset" {
+			return r.finish(err)
+		}
+		return reportDone(ctx, p, r, command, err)
+	case "redo":
+		_, err := p.Redo(ctx)
+		return r.finish(err)
+	}
+	return fmt.Errorf("%q: no such command", command)
+}
+
+// runNoVersioning runs a command with versioning disabled, where migrations are applied in file
+// order without consulting the version table.
+func runNoVersioning(
+	ctx context.Context,
+	p *goose.Provider,
+	r *resultStream,
+	command string,
+	versionArg func() (int64, error),
+) error {
+	sources := p.ListSources()
+	var last int64
+	if len(sources) > 0 {
+		last = sources[len(sources)-1].Version
+	}
+	switch command {
+	case "status":
+		statuses := make([]*goose.MigrationStatus, 0, len(sources))
+		for _, s := range sources {
+			statuses = append(statuses, &goose.MigrationStatus{Source: s})
+		}
+		return r.status(statuses)
+	case "version":
+		return r.version(last)
+	case "up", "up-to":
+		version := last
+		if command == "up-to" {
+			var err error
+			if version, err = versionArg(); err != nil {
+				return err
+			}
+		}
+		_, err := p.UpTo(ctx, version)
+		if err := r.finish(err); err != nil {
+			return err
+		}
+		return r.done(command, fileVersion(sources, version), r.applied)
+	case "down":
+		res, err := p.Down(ctx)
+		if err != nil {
+			return r.finish(err)
+		}
+		if r.err != nil {
+			return r.err
+		}
+		return r.done("down-to", fileVersion(sources, res.Source.Version-1), 1)
+	case "redo":
+		_, err := p.Redo(ctx)
+		return r.finish(err)
+	case "down-to", "reset":
+		var version int64
+		if command == "down-to" {
+			var err error
+			if version, err = versionArg(); err != nil {
+				return err
+			}
+		}
+		// Roll back one file at a time, newest first, stopping at the target version.
+		for i := len(sources) - 1; i >= 0 && sources[i].Version > version; i-- {
+			if _, err := p.ApplyVersion(ctx, sources[i].Version, false); err != nil {
+				return r.finish(err)
+			}
+		}
+		if r.err != nil {
+			return r.err
+		}
+		return r.done("down-to", fileVersion(sources, version), r.applied)
+	case "up-by-one", "history", "doctor", "upgrade-table":
+		return fmt.Errorf("%s not supported when versioning is disabled", command)
+	}
+	return fmt.Errorf("%q: no such command", command)
+}
+
+// fileVersion returns the highest source version not greater than version, or 0 if there is none.
+func fileVersion(sources []*goose.Source, version int64) int64 {
+	var current int64
+	for _, s := range sources {
+		if s.Version <= version {
+			current = s.Version
+		}
+	}
+	return current
+}
+
+// runError formats err for the log. The failed migration of a [goose.PartialError] is named by its
+// file, as the goose package functions do.
+func runError(err error) error {
+	var partialErr *goose.PartialError
+	if errors.As(err, &partialErr) && partialErr.Failed != nil {
+		return fmt.Errorf("ERROR %s: %w", filepath.Base(partialErr.Failed.Source.Path), partialErr.Err)
+	}
+	return err
+}
+
+// resultStream reports the result of every migration to a reporter as soon as the migration
+// finished, see [goose.WithMigrationDone]. The provider is built with its migrationDone method
+// before the reporter of the command is known, which is set before the command runs.
+type resultStream struct {
+	reporter
+	// applied is the number of migrations of the command that succeeded.
+	applied int
+	// err is the first error of the reporter, which stops reporting.
+	err error
+}
+
+func (s *resultStream) migrationDone(res *goose.MigrationResult) {
+	if res.Error == nil {
+		s.applied++
+	}
+	if s.reporter == nil || s.err != nil {
+		return
+	}
+	s.err = s.reporter.result(res)
+}
+
+// finish flushes the reporter after the results of a command, then returns err. An error of the
+// reporter takes precedence.
+func (s *resultStream) finish(err error) error {
+	if s.err != nil {
+		return s.err
+	}
+	if ferr := s.flush(); ferr != nil {
+		return ferr
+	}
+	return err
+}
+
+// reportDone reports the resulting version after up, up-to or down-to, whose results have been
+// reported as they finished.
+func reportDone(
+	ctx context.Context,
+	p *goose.Provider,
+	r *resultStream,
+	command string,
+	err error,
+) error {
+	if err != nil {
+		return r.finish(err)
+	}
+	if r.err != nil {
+		return r.err
+	}
+	current, err := p.GetDBVersion(ctx)
+	if err != nil {
+		return err
+	}
+	return r.done(command, current, r.applied)
+}
@@ -0,0 +1,115 @@
+//go:build !no_sqlite3 && !(windows && arm64)
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCommand(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	fsys := fstest.MapFS{
+		"00001_a.sql": {Data: []byte("-- +goose Up\nCREATE TABLE a (id INTEGER);\n-- +goose Down\nDROP TABLE a;\n")},
+		"00002_b.sql": {Data: []byte("-- +goose Up\nCREATE TABLE b (id INTEGER);\n-- +goose Down\nDROP TABLE b;\n")},
+		"00003_c.sql": {Data: []byte("-- +goose Up\nCREATE TABLE c (id INTEGER);\n-- +goose Down\nDROP TABLE c;\n")},
+	}
+	stream := &resultStream{}
+	p, err := goose.NewProvider(goose.DialectSQLite3, db, fsys, goose.WithMigrationDone(stream.migrationDone))
+	require.NoError(t, err)
+
+	run := func(t *testing.T, command string, args ...string) ([]jsonResult, error) {
+		t.Helper()
+		var buf bytes.Buffer
+		stream.reporter = newJSONOutput(&buf, "", outputJSON)
+		err := runCommand(ctx, p, stream, false, command, args)
+		var results []jsonResult
+		if buf.Len() > 0 {
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &results))
+		}
+		return results, err
+	}
+	versions := func(results []jsonResult) []int64 {
+		var v []int64
+		for _, r := range results {
+			v = append(v, r.Version)
+		}
+		return v
+	}
+
+	results, err := run(t, "up-to", "2")
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, versions(results))
+	results, err = run(t, "up")
+	require.NoError(t, err)
+	require.Equal(t, []int64{3}, versions(results))
+	results, err = run(t, "up")
+	require.NoError(t, err)
+	require.Empty(t, results)
+	_, err = run(t, "up-by-one")
+	require.ErrorIs(t, err, goose.ErrNoNextVersion)
+	results, err = run(t, "redo")
+	require.NoError(t, err)
+	require.Equal(t, []int64{3, 3}, versions(results))
+	require.Equal(t, "down", results[0].Direction)
+	require.Equal(t, "up", results[1].Direction)
+	results, err = run(t, "down-to", "1")
+	require.NoError(t, err)
+	require.Equal(t, []int64{3, 2}, versions(results))
+	results, err = run(t, "reset")
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, versions(results))
+	_, err = run(t, "down")
+	require.ErrorIs(t, err, goose.ErrNoNextVersion)
+	_, err = run(t, "up-to", "x")
+	require.EqualError(t, err, "version must be a number (got 'x')")
+	_, err = run(t, "upgrade-table")
+	require.NoError(t, err)
+	_, err = run(t, "sideways")
+	require.EqualError(t, err, `"sideways": no such command`)
+}
+
+func TestRunDoctor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	fsys := fstest.MapFS{
+		"00001_a.sql": {Data: []byte("-- +goose Up\nCREATE TABLE a (id INTEGER);\n-- +goose Down\nDROP TABLE a;\n")},
+	}
+	p, err := goose.NewProvider(goose.DialectSQLite3, db, fsys)
+	require.NoError(t, err)
+	_, err = p.Up(ctx)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, runCommand(ctx, p, &resultStream{reporter: newJSONOutput(&buf, "", outputJSON)}, false, "doctor", nil))
+	require.JSONEq(t, "[]", buf.String())
+
+	_, err = db.ExecContext(ctx, "INSERT INTO goose_db_version (version_id, is_applied) VALUES (1, true)")
+	require.NoError(t, err)
+	buf.Reset()
+	err = runCommand(ctx, p, &resultStream{reporter: newJSONOutput(&buf, "", outputJSON)}, false, "doctor", nil)
+	require.EqualError(t, err, "doctor found 1 problem(s)")
+	var findings []jsonFinding
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &findings))
+	require.Len(t, findings, 1)
+	require.Equal(t, "duplicate-version", findings[0].Check)
+	require.EqualValues(t, 1, findings[0].Version)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/pressly/goose/v3"
+)
+
+// defaultTemplateDir is where goose init -templates writes the templates when no template directory
+// is configured.
+const defaultTemplateDir = "templates"
+
+// runCreate creates a new migration. If templateDir is set and holds a template for the migration
+// type, sql.tmpl or go.tmpl, it is used instead of the built-in one.
+func runCreate(dir, templateDir string, args []string, vars goose.TemplateVars) error {
+	if len(args) == 0 {
+		return errors.New("create must be of form: goose [OPTIONS] create NAME [go|sql]")
+	}
+	migrationType := "go"
+	if len(args) > 1 {
+		migrationType = args[1]
+	}
+	tmpl, err := loadTemplate(templateDir, migrationType)
+	if err != nil {
+		return err
+	}
+	return goose.CreateWithTemplateVars(nil, dir, tmpl, args[0], migrationType, vars)
+}
+
+// loadTemplate returns the template for the migration type from templateDir, or nil if templateDir
+// is empty or has no template for the type.
+func loadTemplate(templateDir, migrationType string) (*template.Template, error) {
+	if templateDir == "" {
+		return nil, nil
+	}
+	if _, err := os.Stat(templateDir); err != nil {
+		return nil, fmt.Errorf("template dir: %w", err)
+	}
+	path := filepath.Join(templateDir, migrationType+".tmpl")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	tmpl, err := template.New(filepath.Base(path)).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// templateVars returns the extra variables available to create templates.
+func templateVars(driver, ticket string) goose.TemplateVars {
+	vars := goose.TemplateVars{
+		Author: gitAuthor(),
+		Ticket: ticket,
+	}
+	if driver != "" {
+		vars.Dialect = string(dialectFromDriver(driver))
+	}
+	return vars
+}
+
+// gitAuthor returns the git user.name, falling back to the name of the current user.
+func gitAuthor() string {
+	if out, err := exec.Command("git", "config", "user.name").Output(); err == nil {
+		if name := strings.TrimSpace(string(out)); name != "" {
+			return name
+		}
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// initTemplates writes the default create templates to templateDir, so they can be customized.
+func initTemplates(templateDir string) error {
+	if err := os.MkdirAll(templateDir, 0755); err != nil {
+		return err
+	}
+	for name, content := range map[string]string{
+		"sql.tmpl": sqlTemplateScaffold,
+		"go.tmpl":  goTemplateScaffold,
+	} {
+		path := filepath.Join(templateDir, name)
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to create template: %w", err)
+		}
+		if _, err := f.WriteString(content); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		log.Printf("Created new template: %s", path)
+	}
+	return nil
+}
+
+const sqlTemplateScaffold = `-- {{.Name}}
+-- Author: {{.Author}}
+{{- if .Ticket}}
+-- Ticket: {{.Ticket}}
+{{- end}}
+{{- if .Dialect}}
+-- Dialect: {{.Dialect}}
+{{- end}}
+
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+`
+
+const goTemplateScaffold = `// {{.Name}}
+// Author: {{.Author}}
+{{- if .Ticket}}
+// Ticket: {{.Ticket}}
+{{- end}}
+
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(up{{.CamelName}}, down{{.CamelName}})
+}
+
+func up{{.CamelName}}(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	return nil
+}
+
+func down{{.CamelName}}(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	return nil
+}
+`
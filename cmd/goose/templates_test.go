@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTemplate(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := loadTemplate("", "sql")
+	require.NoError(t, err)
+	require.Nil(t, tmpl)
+	_, err = loadTemplate(filepath.Join(t.TempDir(), "missing"), "sql")
+	require.Error(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sql.tmpl"), []byte("-- {{.Name}}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.tmpl"), []byte("{{.Broken"), 0644))
+	tmpl, err = loadTemplate(dir, "sql")
+	require.NoError(t, err)
+	require.NotNil(t, tmpl)
+	_, err = loadTemplate(dir, "go")
+	require.ErrorContains(t, err, "failed to parse template")
+}
+
+func TestCreateWithTemplates(t *testing.T) {
+	t.Parallel()
+
+	templateDir := filepath.Join(t.TempDir(), "templates")
+	require.NoError(t, initTemplates(templateDir))
+	require.Error(t, initTemplates(templateDir), "existing templates must not be overwritten")
+
+	dir := t.TempDir()
+	vars := goose.TemplateVars{Author: "Jane Doe", Ticket: "OPS-12", Dialect: "postgres"}
+	require.NoError(t, runCreate(dir, templateDir, []string{"add_users", "sql"}, vars))
+	files, err := filepath.Glob(filepath.Join(dir, "*_add_users.sql"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	require.Equal(t, `-- add_users
+-- Author: Jane Doe
+-- Ticket: OPS-12
+-- Dialect: postgres
+
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+`, string(data))
+}
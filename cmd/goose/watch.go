@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
+)
+
+// watchInterval is how often goose watch checks the migration file for changes.
+const watchInterval = 500 * time.Millisecond
+
+// watchTarget is the database goose watch re-applies migrations to.
+type watchTarget struct {
+	db      *sql.DB
+	dialect database.Dialect
+	// opts are the options of the provider of each snapshot, such as the locker, besides the ones
+	// from providerOptions.
+	opts []goose.ProviderOption
+	// timeout limits each run of the migration, if not zero.
+	timeout time.Duration
+}
+
+// runWatch applies the newest migration, or the one with the version in args, and re-applies it
+// every time the file changes: it rolls back the migration with the down section of the content
+// last applied, then applies the up section of the new content. It runs until ctx is done.
+func runWatch(
+	ctx context.Context,
+	p *goose.Provider,
+	target watchTarget,
+	dirs map[string]string,
+	primary string,
+	args []string,
+) error {
+	sources := p.ListSources()
+	source := sources[len(sources)-1]
+	if len(args) > 0 {
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("version must be a number (got '%s')", args[0])
+		}
+		if source = findSource(sources, version); source == nil {
+			return fmt.Errorf("version %d: %w", version, goose.ErrVersionNotFound)
+		}
+	}
+	if source.Type != goose.TypeSQL {
+		return fmt.Errorf("watch only supports SQL migrations, %s is a Go migration", filepath.Base(source.Path))
+	}
+	path := sourceFile(source.Path, primary, dirs)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// Bring the database up to the watched migration, so it matches the file from the start.
+	runCtx, cancel := target.context(ctx)
+	results, err := p.UpTo(runCtx, source.Version)
+	cancel()
+	for _, res := range results {
+		logWatchResult(res)
+	}
+	if err != nil {
+		log.Printf("goose: %v", runError(err))
+	}
+	log.Printf("goose: watching %s, press Ctrl+C to stop", path)
+	return watchFile(ctx, path, watchInterval, content, func(old, current []byte) bool {
+		log.Printf("goose: %s changed", filepath.Base(path))
+		runCtx, cancel := target.context(ctx)
+		defer cancel()
+		results, rolledBack, err := reapply(runCtx, target, filepath.Base(source.Path), old, current)
+		for _, res := range results {
+			logWatchResult(res)
+		}
+		if err != nil {
+			log.Printf("goose: %v", runError(err))
+		}
+		return rolledBack
+	})
+}
+
+// context returns the context of a single run of the migration, limited by the timeout.
+func (t watchTarget) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if t.timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, t.timeout)
+}
+
+// watchFile polls the file at path every interval and calls onChange with the content last
+// applied and the current content whenever the content changed, until ctx is done. onChange
+// reports whether the content last applied was rolled back, in which case current becomes the
+// content last applied. Otherwise, the next change is compared with the same content.
+func watchFile(
+	ctx context.Context,
+	path string,
+	interval time.Duration,
+	content []byte,
+	onChange func(old, current []byte) bool,
+) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	// seen is the content onChange was last called with, so a failed change is not retried
+	// until the file changes again.
+	seen := content
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		current, err := os.ReadFile(path)
+		if err != nil {
+			// The file may be replaced by an editor while it is saved, try again on the next tick.
+			continue
+		}
+		if bytes.Equal(current, seen) {
+			continue
+		}
+		seen = current
+		if onChange(content, current) {
+			content = current
+		}
+	}
+}
+
+// reapply rolls back the migration named name with the down section of old, if it is applied, and
+// applies the up section of current. Each content is parsed by a provider of its own, so the
+// rollback runs the statements that were applied, not the edited ones. rolledBack reports whether
+// the migration is no longer applied with old, even if applying current failed.
+func reapply(
+	ctx context.Context,
+	target watchTarget,
+	name string,
+	old, current []byte,
+) (_ []*goose.MigrationResult, rolledBack bool, _ error) {
+	var results []*goose.MigrationResult
+	down, cleanup, err := snapshotProvider(target, name, old)
+	if err != nil {
+		return nil, false, err
+	}
+	defer cleanup()
+	version := down.ListSources()[0].Version
+	res, err := down.ApplyVersion(ctx, version, false)
+	if err != nil && !errors.Is(err, goose.ErrNotApplied) {
+		return nil, false, fmt.Errorf("down: %w", err)
+	}
+	if res != nil {
+		results = append(results, res)
+	}
+	up, cleanup, err := snapshotProvider(target, name, current)
+	if err != nil {
+		return results, true, err
+	}
+	defer cleanup()
+	res, err = up.ApplyVersion(ctx, version, true)
+	if err != nil {
+		return results, true, fmt.Errorf("up: %w", err)
+	}
+	return append(results, res), true, nil
+}
+
+// snapshotProvider returns a provider for a single migration file with the given content, written
+// to a temporary directory that is removed by cleanup. It has the same options as the provider of
+// goose watch, so it takes the same lock.
+func snapshotProvider(
+	target watchTarget,
+	name string,
+	content []byte,
+) (_ *goose.Provider, cleanup func(), _ error) {
+	dir, err := os.MkdirTemp("", "goose-watch")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup = func() { os.RemoveAll(dir) }
+	if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	opts := append(providerOptions(), target.opts...)
+	p, err := goose.NewProvider(target.dialect, target.db, os.DirFS(dir), opts...)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return p, cleanup, nil
+}
+
+func findSource(sources []*goose.Source, version int64) *goose.Source {
+	for _, s := range sources {
+		if s.Version == version {
+			return s
+		}
+	}
+	return nil
+}
+
+func logWatchResult(res *goose.MigrationResult) {
+	if res.Error != nil {
+		return
+	}
+	log.Printf("%-4s %s (%s)", res.Direction, filepath.Base(res.Source.Path), truncateDuration(res.Duration))
+}
@@ -0,0 +1,106 @@
+//go:build !no_sqlite3 && !(windows && arm64)
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pressly/goose/v3/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReapply(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	target := watchTarget{db: db, dialect: database.DialectSQLite3}
+	const name = "00001_users.sql"
+	v1 := []byte("-- +goose Up\nCREATE TABLE users (id INTEGER);\n-- +goose Down\nDROP TABLE users;\n")
+	v2 := []byte("-- +goose Up\nCREATE TABLE accounts (id INTEGER);\n-- +goose Down\nDROP TABLE accounts;\n")
+
+	// Not applied yet, so there is nothing to roll back.
+	results, rolledBack, err := reapply(ctx, target, name, nil, v1)
+	require.NoError(t, err)
+	require.True(t, rolledBack)
+	require.Len(t, results, 1)
+	require.Equal(t, "up", results[0].Direction)
+
+	// The rollback uses the down section of the previous content, which dropped users.
+	results, rolledBack, err = reapply(ctx, target, name, v1, v2)
+	require.NoError(t, err)
+	require.True(t, rolledBack)
+	require.Len(t, results, 2)
+	require.Equal(t, "down", results[0].Direction)
+	require.Equal(t, "up", results[1].Direction)
+	tables := func() []string {
+		var tables []string
+		rows, err := db.QueryContext(ctx, "SELECT name FROM sqlite_master WHERE name IN ('users', 'accounts')")
+		require.NoError(t, err)
+		for rows.Next() {
+			var name string
+			require.NoError(t, rows.Scan(&name))
+			tables = append(tables, name)
+		}
+		require.NoError(t, rows.Err())
+		return tables
+	}
+	require.Equal(t, []string{"accounts"}, tables())
+
+	// A broken down section leaves the migration applied, so it is not rolled back.
+	_, rolledBack, err = reapply(ctx, target, name, []byte("-- +goose Up\n-- +goose Down\nDROP TABLE;\n"), v1)
+	require.Error(t, err)
+	require.False(t, rolledBack)
+	require.Equal(t, []string{"accounts"}, tables())
+
+	// A broken up section leaves the migration rolled back.
+	_, rolledBack, err = reapply(ctx, target, name, v2, []byte("-- +goose Up\nCREATE TABLE;\n"))
+	require.Error(t, err)
+	require.True(t, rolledBack)
+	results, _, err = reapply(ctx, target, name, nil, v1)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+}
+
+func TestWatchFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "00001_a.sql")
+	require.NoError(t, os.WriteFile(path, []byte("a"), 0644))
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan [2]string, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- watchFile(ctx, path, 10*time.Millisecond, []byte("a"), func(old, current []byte) bool {
+			changes <- [2]string{string(old), string(current)}
+			// The change to "b" fails to roll back "a".
+			return string(current) != "b"
+		})
+	}()
+	next := func() [2]string {
+		select {
+		case change := <-changes:
+			return change
+		case <-time.After(5 * time.Second):
+			t.Fatal("change not detected")
+			return [2]string{}
+		}
+	}
+	require.NoError(t, os.WriteFile(path, []byte("b"), 0644))
+	require.Equal(t, [2]string{"a", "b"}, next())
+	// "a" is still applied, so the next change rolls it back again, and "b" is not retried.
+	require.NoError(t, os.WriteFile(path, []byte("c"), 0644))
+	require.Equal(t, [2]string{"a", "c"}, next())
+	require.NoError(t, os.WriteFile(path, []byte("d"), 0644))
+	require.Equal(t, [2]string{"c", "d"}, next())
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -11,8 +11,21 @@ import (
 )
 
 type tmplVars struct {
+	TemplateVars
 	Version   string
 	CamelName string
+	Name      string
+}
+
+// TemplateVars are additional values available to migration templates, next to .Version,
+// .CamelName and .Name (the snake_case migration name).
+type TemplateVars struct {
+	// Author of the migration, e.g., from git config user.name.
+	Author string
+	// Ticket is an issue or ticket id the migration belongs to.
+	Ticket string
+	// Dialect is the database dialect the migration is written for, if known.
+	Dialect string
 }
 
 var (
@@ -26,6 +39,18 @@ func SetSequential(s bool) {
 
 // Create writes a new blank migration file.
 func CreateWithTemplate(db *sql.DB, dir string, tmpl *template.Template, name, migrationType string) error {
+	return CreateWithTemplateVars(db, dir, tmpl, name, migrationType, TemplateVars{})
+}
+
+// CreateWithTemplateVars writes a new migration file like [CreateWithTemplate], and makes vars
+// available to the template.
+func CreateWithTemplateVars(
+	db *sql.DB,
+	dir string,
+	tmpl *template.Template,
+	name, migrationType string,
+	vars TemplateVars,
+) error {
 	version := time.Now().UTC().Format(timestampFormat)
 
 	if sequential {
@@ -68,11 +93,13 @@ func CreateWithTemplate(db *sql.DB, dir string, tmpl *template.Template, name, m
 	}
 	defer f.Close()
 
-	vars := tmplVars{
-		Version:   version,
-		CamelName: camelCase(name),
+	data := tmplVars{
+		TemplateVars: vars,
+		Version:      version,
+		CamelName:    camelCase(name),
+		Name:         snakeCase(name),
 	}
-	if err := tmpl.Execute(f, vars); err != nil {
+	if err := tmpl.Execute(f, data); err != nil {
 		return fmt.Errorf("failed to execute tmpl: %w", err)
 	}
 
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"github.com/pressly/goose/v3/internal/dialects"
+)
+
+// ClickHouseOptions configures the version table of the ClickHouse dialect for a cluster.
+type ClickHouseOptions struct {
+	// Cluster is the name of the cluster to create the version table on. If set, the table is
+	// created ON CLUSTER with a ReplicatedMergeTree engine.
+	Cluster string
+	// ZooKeeperPath is the path of the replicated table in ClickHouse Keeper or ZooKeeper. The
+	// default is "/clickhouse/tables/{database}/{table}", which replicates the table to every node
+	// of the cluster.
+	ZooKeeperPath string
+	// ReplicaName is the name of the replica. The default is the "{replica}" macro.
+	ReplicaName string
+
+	// Final reads the version table with FINAL.
+	Final bool
+	// SequentialConsistency writes versions with insert_quorum and reads them with
+	// select_sequential_consistency, so a replica never returns a stale migration history.
+	SequentialConsistency bool
+}
+
+// NewClickHouseStore returns a new [Store] for the ClickHouse dialect configured with opts. The
+// zero options are the same as NewStore(DialectClickHouse, tableName).
+func NewClickHouseStore(tableName string, opts ClickHouseOptions) (Store, error) {
+	return newStore(tableName, dialects.NewClickhouseWithOptions(dialects.ClickhouseOptions{
+		Cluster:               opts.Cluster,
+		ZooKeeperPath:         opts.ZooKeeperPath,
+		ReplicaName:           opts.ReplicaName,
+		Final:                 opts.Final,
+		SequentialConsistency: opts.SequentialConsistency,
+	}), nil)
+}
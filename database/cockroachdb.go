@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3/internal/cockroachdb"
+)
+
+// cockroachDBStore retries version table writes that CockroachDB aborts with a transaction restart
+// error (SQLSTATE 40001), which happens when concurrent transactions conflict under serializable
+// isolation.
+//
+// Only writes outside of a transaction are retried. Within a transaction the restart error aborts
+// the whole transaction, including the migration itself, so it is returned to the caller, which
+// retries the transaction. The goose Provider does so for migrations run in a transaction.
+type cockroachDBStore struct {
+	*store
+}
+
+var _ StoreExtender = (*cockroachDBStore)(nil)
+
+func (s *cockroachDBStore) Insert(ctx context.Context, db DBTxConn, req InsertRequest) error {
+	if _, ok := db.(*sql.Tx); ok {
+		return s.store.Insert(ctx, db, req)
+	}
+	return cockroachdb.Retry(ctx, func(ctx context.Context) error {
+		return s.store.Insert(ctx, db, req)
+	})
+}
+
+func (s *cockroachDBStore) Delete(ctx context.Context, db DBTxConn, version int64) error {
+	if _, ok := db.(*sql.Tx); ok {
+		return s.store.Delete(ctx, db, version)
+	}
+	return cockroachdb.Retry(ctx, func(ctx context.Context) error {
+		return s.store.Delete(ctx, db, version)
+	})
+}
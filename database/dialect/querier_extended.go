@@ -20,3 +20,72 @@ type QuerierExtender interface {
 	// string if not supported.
 	TableExists(tableName string) string
 }
+
+// HistoryLister is an optional interface of a [Querier] that lists the full history of the version
+// table, for goose history.
+type HistoryLister interface {
+	// ListHistory returns the SQL query string to list every row of the version table in insertion
+	// order, oldest first, by id or, if the table has none, by tstamp. The query should return the
+	// version_id, is_applied and tstamp columns.
+	// Return empty string if not supported.
+	ListHistory(tableName string) string
+}
+
+// FutureHistoryLister is an optional interface of a [Querier] that lists the rows of the version
+// table recorded in the future, for goose doctor. The comparison runs in the database, so it is not
+// affected by the clock or time zone of the client.
+type FutureHistoryLister interface {
+	// ListFutureHistory returns the SQL query string to list the rows of the version table whose
+	// tstamp is later than the current time of the database, in insertion order, oldest first. The
+	// current time must be in the same representation as the default of the tstamp column. The
+	// query should return the version_id, is_applied and tstamp columns. Return empty string if not
+	// supported.
+	ListFutureHistory(tableName string) string
+}
+
+// TableUpgrader is an optional interface of a [Querier] that evolves the layout of the version
+// table across goose releases, e.g., by adding a column or an index. CreateTable always creates
+// the original layout, layout 1, and each upgrade moves the table to the next layout.
+//
+// New version tables are created with every upgrade applied. Existing tables are only upgraded
+// explicitly, with Provider.UpgradeTable or goose upgrade-table.
+type TableUpgrader interface {
+	// TableUpgrades returns the upgrades of the version table in order. Upgrade i moves the table
+	// from layout i+1 to layout i+2.
+	TableUpgrades(tableName string) []TableUpgrade
+}
+
+// TableUpgrade is a single change to the layout of the version table.
+type TableUpgrade struct {
+	// Check returns a single boolean, true if the upgrade was already applied. It must not fail
+	// if the upgrade was not applied, so it can run inside a transaction.
+	Check string
+	// Statements apply the upgrade, in order.
+	Statements []string
+}
+
+// SchemaCreator is an optional interface of a [Querier] for databases with schemas. It creates the
+// schema of a schema-qualified version table, such as "migrations.goose_db_version", for stores
+// created with database.WithCreateSchema.
+type SchemaCreator interface {
+	// CreateSchema returns the SQL query string to create the schema of tableName if it does not
+	// exist, owned by owner, or by the current user if owner is empty. Return empty string if
+	// tableName is not schema-qualified.
+	CreateSchema(tableName, owner string) string
+}
+
+// TypeRecorder is an optional interface of a [Querier] that records the type of each migration,
+// "sql" or "go", in a migration_type column of the version table, so goose doctor can report a
+// migration whose type changed after it was applied. The column is added by one of the upgrades of
+// [TableUpgrader], and the queries below are only used once the version table has it.
+type TypeRecorder interface {
+	// TypeColumnExists returns the SQL query string to check whether the version table has the
+	// migration_type column. Returns a single boolean.
+	TypeColumnExists(tableName string) string
+	// InsertVersionType returns the SQL query string to insert a new version with its type. The
+	// arguments are the version_id, is_applied and migration_type, in that order.
+	InsertVersionType(tableName string) string
+	// ListTypes returns the SQL query string to list the version_id and migration_type of every
+	// row that records a type, oldest first.
+	ListTypes(tableName string) string
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/pressly/goose/v3/database/dialect"
 	"github.com/pressly/goose/v3/internal/dialects"
@@ -14,49 +16,109 @@ import (
 type Dialect string
 
 const (
-	DialectCustom     Dialect = ""
-	DialectClickHouse Dialect = "clickhouse"
-	DialectAuroraDSQL Dialect = "dsql"
-	DialectMSSQL      Dialect = "mssql"
-	DialectMySQL      Dialect = "mysql"
-	DialectPostgres   Dialect = "postgres"
-	DialectRedshift   Dialect = "redshift"
-	DialectSQLite3    Dialect = "sqlite3"
-	DialectSpanner    Dialect = "spanner"
-	DialectStarrocks  Dialect = "starrocks"
-	DialectTiDB       Dialect = "tidb"
-	DialectTurso      Dialect = "turso"
-	DialectYdB        Dialect = "ydb"
+	DialectCustom      Dialect = ""
+	DialectClickHouse  Dialect = "clickhouse"
+	DialectCockroachDB Dialect = "cockroachdb"
+	DialectAuroraDSQL  Dialect = "dsql"
+	DialectDuckDB      Dialect = "duckdb"
+	DialectMSSQL       Dialect = "mssql"
+	DialectMySQL       Dialect = "mysql"
+	DialectOracle      Dialect = "oracle"
+	DialectPostgres    Dialect = "postgres"
+	DialectRedshift    Dialect = "redshift"
+	DialectSQLite3     Dialect = "sqlite3"
+	DialectSpanner     Dialect = "spanner"
+	DialectStarrocks   Dialect = "starrocks"
+	DialectTiDB        Dialect = "tidb"
+	DialectTurso       Dialect = "turso"
+	DialectYdB         Dialect = "ydb"
 
 	// DEPRECATED: Vertica support is deprecated and will be removed in a future release.
 	DialectVertica Dialect = "vertica"
 )
 
-// NewStore returns a new [Store] implementation for the given dialect.
-func NewStore(d Dialect, tableName string) (Store, error) {
+// NewStore returns a new [Store] implementation for the given dialect, which is either one of the
+// dialects above or a dialect added with [RegisterDialect].
+func NewStore(d Dialect, tableName string, opts ...StoreOption) (Store, error) {
 	if d == DialectCustom {
 		return nil, errors.New("custom dialect is not supported")
 	}
-	lookup := map[Dialect]dialect.Querier{
-		DialectClickHouse: dialects.NewClickhouse(),
-		DialectAuroraDSQL: dialects.NewAuroraDSQL(),
-		DialectMSSQL:      dialects.NewSqlserver(),
-		DialectMySQL:      dialects.NewMysql(),
-		DialectPostgres:   dialects.NewPostgres(),
-		DialectRedshift:   dialects.NewRedshift(),
-		DialectSQLite3:    dialects.NewSqlite3(),
-		DialectSpanner:    dialects.NewSpanner(),
-		DialectStarrocks:  dialects.NewStarrocks(),
-		DialectTiDB:       dialects.NewTidb(),
-		DialectTurso:      dialects.NewTurso(),
-		DialectVertica:    dialects.NewVertica(),
-		DialectYdB:        dialects.NewYDB(),
-	}
-	querier, ok := lookup[d]
+	querier, ok := LookupDialect(d)
 	if !ok {
 		return nil, fmt.Errorf("unknown dialect: %q", d)
 	}
-	return NewStoreFromQuerier(tableName, querier)
+	s, err := newStore(tableName, querier, opts)
+	if err != nil {
+		return nil, err
+	}
+	if d == DialectCockroachDB {
+		return &cockroachDBStore{store: s}, nil
+	}
+	return s, nil
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[Dialect]dialect.Querier{
+		DialectClickHouse:  dialects.NewClickhouse(),
+		DialectCockroachDB: dialects.NewCockroachDB(),
+		DialectAuroraDSQL:  dialects.NewAuroraDSQL(),
+		DialectDuckDB:      dialects.NewDuckDB(),
+		DialectMSSQL:       dialects.NewSqlserver(),
+		DialectMySQL:       dialects.NewMysql(),
+		DialectOracle:      dialects.NewOracle(),
+		DialectPostgres:    dialects.NewPostgres(),
+		DialectRedshift:    dialects.NewRedshift(),
+		DialectSQLite3:     dialects.NewSqlite3(),
+		DialectSpanner:     dialects.NewSpanner(),
+		DialectStarrocks:   dialects.NewStarrocks(),
+		DialectTiDB:        dialects.NewTidb(),
+		DialectTurso:       dialects.NewTurso(),
+		DialectVertica:     dialects.NewVertica(),
+		DialectYdB:         dialects.NewYDB(),
+	}
+)
+
+// RegisterDialect makes a dialect available by name to [NewStore], and with it to goose.NewProvider
+// and the goose CLI. It is meant to be called from the init function of a package that adds
+// support for a database, similar to [sql.Register].
+//
+// If RegisterDialect is called twice with the same name, if the name is empty, or if querier is
+// nil, it panics.
+func RegisterDialect(name Dialect, querier dialect.Querier) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if name == DialectCustom {
+		panic("goose: RegisterDialect dialect name is empty")
+	}
+	if querier == nil {
+		panic("goose: RegisterDialect querier is nil")
+	}
+	if _, dup := registry[name]; dup {
+		panic("goose: RegisterDialect called twice for dialect " + string(name))
+	}
+	registry[name] = querier
+}
+
+// LookupDialect returns the querier of a built-in or registered dialect, and whether the dialect
+// exists.
+func LookupDialect(name Dialect) (dialect.Querier, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	querier, ok := registry[name]
+	return querier, ok
+}
+
+// Dialects returns the names of the built-in and registered dialects, sorted.
+func Dialects() []Dialect {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]Dialect, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
 }
 
 // NewStoreFromQuerier returns a new [Store] implementation for the given querier.
@@ -64,39 +126,121 @@ func NewStore(d Dialect, tableName string) (Store, error) {
 // Most of the time you should use [NewStore] instead of this function, as it will automatically
 // create a dialect-specific querier for you. This function is useful if you want to use a custom
 // querier that is not part of the standard dialects.
-func NewStoreFromQuerier(tableName string, querier dialect.Querier) (Store, error) {
+func NewStoreFromQuerier(tableName string, querier dialect.Querier, opts ...StoreOption) (Store, error) {
+	return newStore(tableName, querier, opts)
+}
+
+func newStore(tableName string, querier dialect.Querier, opts []StoreOption) (*store, error) {
 	if tableName == "" {
 		return nil, errors.New("table name must not be empty")
 	}
 	if querier == nil {
 		return nil, errors.New("querier must not be nil")
 	}
+	var cfg storeConfig
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	if _, ok := querier.(dialect.SchemaCreator); cfg.createSchema && !ok {
+		return nil, errors.New("querier does not support creating the schema of the version table")
+	}
 	return &store{
 		tableName: tableName,
 		querier:   newQueryController(querier),
+		cfg:       cfg,
 	}, nil
 }
 
+// StoreOption configures a [Store] returned by [NewStore] or [NewStoreFromQuerier].
+type StoreOption interface {
+	apply(*storeConfig)
+}
+
+type storeConfig struct {
+	createSchema bool
+	schemaOwner  string
+}
+
+type storeConfigFunc func(*storeConfig)
+
+func (f storeConfigFunc) apply(cfg *storeConfig) {
+	f(cfg)
+}
+
+// WithCreateSchema creates the schema of a schema-qualified version table, such as
+// "migrations.goose_db_version", if it does not exist, right before the table is created. owner is
+// the owner of a new schema, or empty for the current user.
+//
+// It requires a querier that implements [dialect.SchemaCreator], which the Postgres, Redshift,
+// Aurora DSQL and CockroachDB dialects do.
+func WithCreateSchema(owner string) StoreOption {
+	return storeConfigFunc(func(c *storeConfig) {
+		c.createSchema = true
+		c.schemaOwner = owner
+	})
+}
+
 type store struct {
 	tableName string
 	querier   *queryController
+	cfg       storeConfig
+
+	// typeColumn caches whether the version table has the migration_type column of
+	// dialect.TypeRecorder. It is nil until checked, and reset when the table is created or
+	// upgraded.
+	typeMu     sync.Mutex
+	typeColumn *bool
 }
 
-var _ Store = (*store)(nil)
+var (
+	_ Store               = (*store)(nil)
+	_ HistoryLister       = (*store)(nil)
+	_ FutureHistoryLister = (*store)(nil)
+	_ TypeLister          = (*store)(nil)
+	_ TableUpgrader       = (*store)(nil)
+)
 
 func (s *store) Tablename() string {
 	return s.tableName
 }
 
 func (s *store) CreateVersionTable(ctx context.Context, db DBTxConn) error {
+	if s.cfg.createSchema {
+		if q := s.querier.CreateSchema(s.tableName, s.cfg.schemaOwner); q != "" {
+			if _, err := db.ExecContext(ctx, q); err != nil {
+				return fmt.Errorf("failed to create schema of version table %q: %w", s.tableName, err)
+			}
+		}
+	}
 	q := s.querier.CreateTable(s.tableName)
 	if _, err := db.ExecContext(ctx, q); err != nil {
 		return fmt.Errorf("failed to create version table %q: %w", s.tableName, err)
 	}
+	s.resetTypeColumn()
+	// CreateTable creates the original layout, bring the new table to the latest one.
+	upgrades := s.querier.TableUpgrades(s.tableName)
+	for i, upgrade := range upgrades {
+		if err := s.applyUpgrade(ctx, db, upgrade, i+2); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 func (s *store) Insert(ctx context.Context, db DBTxConn, req InsertRequest) error {
+	if req.Type != "" {
+		ok, err := s.hasTypeColumn(ctx, db)
+		if err != nil {
+			return err
+		}
+		if ok {
+			q := s.querier.InsertVersionType(s.tableName)
+			if _, err := db.ExecContext(ctx, q, req.Version, true, req.Type); err != nil {
+				return fmt.Errorf("failed to insert version %d: %w", req.Version, err)
+			}
+			return nil
+		}
+	}
 	q := s.querier.InsertVersion(s.tableName)
 	if _, err := db.ExecContext(ctx, q, req.Version, true); err != nil {
 		return fmt.Errorf("failed to insert version %d: %w", req.Version, err)
@@ -191,6 +335,140 @@ func (s *store) TableExists(ctx context.Context, db DBTxConn) (bool, error) {
 	return exists, nil
 }
 
+func (s *store) ListHistory(ctx context.Context, db DBTxConn) ([]*HistoryResult, error) {
+	q := s.querier.ListHistory(s.tableName)
+	if q == "" {
+		return nil, errors.ErrUnsupported
+	}
+	history, err := listHistory(ctx, db, q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list history: %w", err)
+	}
+	return history, nil
+}
+
+func (s *store) ListFutureHistory(ctx context.Context, db DBTxConn) ([]*HistoryResult, error) {
+	q := s.querier.ListFutureHistory(s.tableName)
+	if q == "" {
+		return nil, errors.ErrUnsupported
+	}
+	history, err := listHistory(ctx, db, q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list future history: %w", err)
+	}
+	return history, nil
+}
+
+// listHistory runs a query that returns the version_id, is_applied and tstamp columns.
+func listHistory(ctx context.Context, db DBTxConn, q string) ([]*HistoryResult, error) {
+	rows, err := db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*HistoryResult
+	for rows.Next() {
+		var result HistoryResult
+		var timestamp sql.NullTime
+		if err := rows.Scan(&result.Version, &result.IsApplied, &timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan history result: %w", err)
+		}
+		result.Timestamp = timestamp.Time
+		history = append(history, &result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (s *store) ListTypes(ctx context.Context, db DBTxConn) (map[int64]string, error) {
+	ok, err := s.hasTypeColumn(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.ErrUnsupported
+	}
+	rows, err := db.QueryContext(ctx, s.querier.ListTypes(s.tableName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migration types: %w", err)
+	}
+	defer rows.Close()
+
+	types := make(map[int64]string)
+	for rows.Next() {
+		var version int64
+		var typ string
+		if err := rows.Scan(&version, &typ); err != nil {
+			return nil, fmt.Errorf("failed to scan migration type: %w", err)
+		}
+		types[version] = typ
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return types, nil
+}
+
+// hasTypeColumn reports whether the querier records migration types and the version table has the
+// migration_type column for them.
+func (s *store) hasTypeColumn(ctx context.Context, db DBTxConn) (bool, error) {
+	q := s.querier.TypeColumnExists(s.tableName)
+	if q == "" {
+		return false, nil
+	}
+	s.typeMu.Lock()
+	defer s.typeMu.Unlock()
+	if s.typeColumn == nil {
+		var exists bool
+		if err := db.QueryRowContext(ctx, q).Scan(&exists); err != nil {
+			return false, fmt.Errorf("failed to check for the migration_type column: %w", err)
+		}
+		s.typeColumn = &exists
+	}
+	return *s.typeColumn, nil
+}
+
+func (s *store) resetTypeColumn() {
+	s.typeMu.Lock()
+	defer s.typeMu.Unlock()
+	s.typeColumn = nil
+}
+
+func (s *store) UpgradeTable(ctx context.Context, db DBTxConn) (*TableUpgradeResult, error) {
+	defer s.resetTypeColumn()
+	upgrades := s.querier.TableUpgrades(s.tableName)
+	latest := len(upgrades) + 1
+	// The layout is the one before the first upgrade that was not applied. Every upgrade is
+	// checked, so one applied out of order, e.g., by hand, is not applied again.
+	from := latest
+	for i, upgrade := range upgrades {
+		var applied bool
+		if err := db.QueryRowContext(ctx, upgrade.Check).Scan(&applied); err != nil {
+			return nil, fmt.Errorf("failed to check layout %d of version table %q: %w", i+2, s.tableName, err)
+		}
+		if applied {
+			continue
+		}
+		from = min(from, i+1)
+		if err := s.applyUpgrade(ctx, db, upgrade, i+2); err != nil {
+			return nil, err
+		}
+	}
+	return &TableUpgradeResult{From: from, To: latest}, nil
+}
+
+func (s *store) applyUpgrade(ctx context.Context, db DBTxConn, upgrade dialect.TableUpgrade, layout int) error {
+	for _, q := range upgrade.Statements {
+		if _, err := db.ExecContext(ctx, q); err != nil {
+			return fmt.Errorf("failed to upgrade version table %q to layout %d: %w", s.tableName, layout, err)
+		}
+	}
+	return nil
+}
+
 var _ dialect.Querier = (*queryController)(nil)
 
 type queryController struct{ dialect.Querier }
@@ -212,3 +490,65 @@ func (c *queryController) TableExists(tableName string) string {
 	}
 	return ""
 }
+
+// ListHistory returns the SQL query string to list every row of the version table in insertion
+// order. If the Querier does not implement this method, it will return an empty string.
+//
+// Returns the version_id, is_applied and tstamp columns.
+func (c *queryController) ListHistory(tableName string) string {
+	if t, ok := c.Querier.(dialect.HistoryLister); ok {
+		return t.ListHistory(tableName)
+	}
+	return ""
+}
+
+// ListFutureHistory returns the SQL query string to list the rows of the version table recorded
+// later than the current time of the database. If the Querier does not implement this method, it
+// will return an empty string.
+//
+// Returns the version_id, is_applied and tstamp columns.
+func (c *queryController) ListFutureHistory(tableName string) string {
+	if t, ok := c.Querier.(dialect.FutureHistoryLister); ok {
+		return t.ListFutureHistory(tableName)
+	}
+	return ""
+}
+
+// TableUpgrades returns the upgrades of the version table layout. If the Querier does not implement
+// this method, it will return no upgrades.
+func (c *queryController) TableUpgrades(tableName string) []dialect.TableUpgrade {
+	if t, ok := c.Querier.(dialect.TableUpgrader); ok {
+		return t.TableUpgrades(tableName)
+	}
+	return nil
+}
+
+// CreateSchema returns the SQL query string to create the schema of the version table. If the
+// Querier does not implement this method, it will return an empty string.
+func (c *queryController) CreateSchema(tableName, owner string) string {
+	if t, ok := c.Querier.(dialect.SchemaCreator); ok {
+		return t.CreateSchema(tableName, owner)
+	}
+	return ""
+}
+
+// TypeColumnExists returns the SQL query string to check for the migration_type column. If the
+// Querier does not implement [dialect.TypeRecorder], it will return an empty string.
+func (c *queryController) TypeColumnExists(tableName string) string {
+	if t, ok := c.Querier.(dialect.TypeRecorder); ok {
+		return t.TypeColumnExists(tableName)
+	}
+	return ""
+}
+
+// InsertVersionType returns the SQL query string to insert a version with its type. It must only
+// be called if TypeColumnExists returns a query.
+func (c *queryController) InsertVersionType(tableName string) string {
+	return c.Querier.(dialect.TypeRecorder).InsertVersionType(tableName)
+}
+
+// ListTypes returns the SQL query string to list the recorded migration types. It must only be
+// called if TypeColumnExists returns a query.
+func (c *queryController) ListTypes(tableName string) string {
+	return c.Querier.(dialect.TypeRecorder).ListTypes(tableName)
+}
@@ -0,0 +1,63 @@
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+)
+
+// OpenDB returns a database that accepts every statement without running it. Statements succeed
+// with no rows affected, queries return no rows, and transactions always commit.
+//
+// It stands in for a real database when the version table is kept in a [Store], so a Provider can
+// run without a database driver.
+func OpenDB() *sql.DB {
+	return sql.OpenDB(connector{})
+}
+
+type connector struct{}
+
+func (connector) Connect(context.Context) (driver.Conn, error) { return conn{}, nil }
+func (connector) Driver() driver.Driver                        { return nopDriver{} }
+
+type nopDriver struct{}
+
+func (nopDriver) Open(string) (driver.Conn, error) { return conn{}, nil }
+
+type conn struct{}
+
+var (
+	_ driver.ExecerContext  = conn{}
+	_ driver.QueryerContext = conn{}
+)
+
+func (conn) Prepare(string) (driver.Stmt, error) { return stmt{}, nil }
+func (conn) Close() error                        { return nil }
+func (conn) Begin() (driver.Tx, error)           { return tx{}, nil }
+
+func (conn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+func (conn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return rows{}, nil
+}
+
+type stmt struct{}
+
+func (stmt) Close() error                               { return nil }
+func (stmt) NumInput() int                              { return -1 }
+func (stmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(0), nil }
+func (stmt) Query([]driver.Value) (driver.Rows, error)  { return rows{}, nil }
+
+type tx struct{}
+
+func (tx) Commit() error   { return nil }
+func (tx) Rollback() error { return nil }
+
+type rows struct{}
+
+func (rows) Columns() []string         { return nil }
+func (rows) Close() error              { return nil }
+func (rows) Next([]driver.Value) error { return io.EOF }
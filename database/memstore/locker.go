@@ -0,0 +1,48 @@
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pressly/goose/v3/lock"
+)
+
+// Locker is an in-memory [lock.Locker]. Providers that share a Locker run one at a time, like
+// providers that share a database with a table locker. The database passed to Lock and Unlock is
+// ignored.
+type Locker struct {
+	ch chan struct{}
+}
+
+var _ lock.Locker = (*Locker)(nil)
+
+// NewLocker returns a new unlocked Locker.
+func NewLocker() *Locker {
+	return &Locker{ch: make(chan struct{}, 1)}
+}
+
+// Lock acquires the lock, waiting until it is released or ctx is done.
+func (l *Locker) Lock(ctx context.Context, _ *sql.DB) error {
+	select {
+	case l.ch <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Unlock releases the lock. It returns an error if the lock is not held.
+func (l *Locker) Unlock(context.Context, *sql.DB) error {
+	select {
+	case <-l.ch:
+		return nil
+	default:
+		return errors.New("memstore: unlock of unlocked locker")
+	}
+}
+
+// Locked reports whether the lock is held.
+func (l *Locker) Locked() bool {
+	return len(l.ch) == 1
+}
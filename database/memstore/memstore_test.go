@@ -0,0 +1,133 @@
+package memstore_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
+	"github.com/pressly/goose/v3/database/memstore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"00001_a.sql": {Data: []byte("-- +goose Up\nCREATE TABLE a (id int);\n-- +goose Down\nDROP TABLE a;\n")},
+		"00002_b.sql": {Data: []byte("-- +goose Up\nCREATE TABLE b (id int);\n-- +goose Down\nDROP TABLE b;\n")},
+		"00003_c.sql": {Data: []byte("-- +goose Up\nCREATE TABLE c (id int);\n-- +goose Down\nDROP TABLE c;\n")},
+	}
+	newProvider := func(t *testing.T, store *memstore.Store, opts ...goose.ProviderOption) *goose.Provider {
+		t.Helper()
+		opts = append([]goose.ProviderOption{goose.WithStore(store), goose.WithLocker(memstore.NewLocker())}, opts...)
+		p, err := goose.NewProvider(goose.DialectCustom, memstore.OpenDB(), fsys, opts...)
+		require.NoError(t, err)
+		return p
+	}
+	ctx := context.Background()
+
+	t.Run("up and down", func(t *testing.T) {
+		store := memstore.New(goose.DefaultTablename)
+		p := newProvider(t, store)
+		results, err := p.Up(ctx)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		version, err := p.GetDBVersion(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, version)
+		results, err = p.DownTo(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		migrations, err := store.ListMigrations(ctx, nil)
+		require.NoError(t, err)
+		require.Equal(t, []*database.ListMigrationsResult{
+			{Version: 1, IsApplied: true},
+			{Version: 0, IsApplied: true},
+		}, migrations)
+	})
+	t.Run("out of order", func(t *testing.T) {
+		store := memstore.New(goose.DefaultTablename)
+		require.NoError(t, store.CreateVersionTable(ctx, nil))
+		for _, v := range []int64{0, 1, 3} {
+			require.NoError(t, store.Insert(ctx, nil, database.InsertRequest{Version: v}))
+		}
+		_, err := newProvider(t, store).Up(ctx)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing (out-of-order) migration")
+		results, err := newProvider(t, store, goose.WithAllowOutofOrder(true)).Up(ctx)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.EqualValues(t, 2, results[0].Source.Version)
+	})
+	t.Run("partial error", func(t *testing.T) {
+		store := memstore.New(goose.DefaultTablename)
+		fail := goose.NewGoMigration(4, &goose.GoFunc{
+			RunTx: func(context.Context, *sql.Tx) error { return errors.New("boom") },
+		}, nil)
+		_, err := newProvider(t, store, goose.WithGoMigrations(fail)).Up(ctx)
+		var partialErr *goose.PartialError
+		require.ErrorAs(t, err, &partialErr)
+		require.Len(t, partialErr.Applied, 3)
+		require.EqualValues(t, 4, partialErr.Failed.Source.Version)
+		latest, err := store.GetLatestVersion(ctx, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, latest)
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := memstore.New("foo")
+	require.Equal(t, "foo", store.Tablename())
+	exists, err := store.TableExists(ctx, nil)
+	require.NoError(t, err)
+	require.False(t, exists)
+	require.Error(t, store.Insert(ctx, nil, database.InsertRequest{Version: 1}))
+	require.NoError(t, store.CreateVersionTable(ctx, nil))
+	require.Error(t, store.CreateVersionTable(ctx, nil))
+
+	_, err = store.GetLatestVersion(ctx, nil)
+	require.ErrorIs(t, err, database.ErrVersionNotFound)
+	_, err = store.GetMigration(ctx, nil, 1)
+	require.ErrorIs(t, err, database.ErrVersionNotFound)
+	for _, v := range []int64{0, 2, 1} {
+		require.NoError(t, store.Insert(ctx, nil, database.InsertRequest{Version: v}))
+	}
+	latest, err := store.GetLatestVersion(ctx, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, latest)
+	m, err := store.GetMigration(ctx, nil, 1)
+	require.NoError(t, err)
+	require.True(t, m.IsApplied)
+	history, err := store.ListHistory(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	require.EqualValues(t, 2, history[1].Version)
+	require.NoError(t, store.Delete(ctx, nil, 2))
+	latest, err = store.GetLatestVersion(ctx, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, latest)
+}
+
+func TestLocker(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := memstore.NewLocker()
+	require.NoError(t, l.Lock(ctx, nil))
+	require.True(t, l.Locked())
+	// A second Lock waits until the context is done.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, l.Lock(timeoutCtx, nil), context.DeadlineExceeded)
+	require.NoError(t, l.Unlock(ctx, nil))
+	require.False(t, l.Locked())
+	require.Error(t, l.Unlock(ctx, nil))
+}
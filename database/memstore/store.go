@@ -0,0 +1,190 @@
+// Package memstore provides in-memory implementations of [database.Store] and [lock.Locker], and a
+// database that accepts every statement, to unit test code built on a goose Provider without a
+// database driver:
+//
+//	p, err := goose.NewProvider(goose.DialectCustom, memstore.OpenDB(), fsys,
+//		goose.WithStore(memstore.New(goose.DefaultTablename)),
+//		goose.WithLocker(memstore.NewLocker()),
+//	)
+//
+// SQL migrations are not executed, so this is meant for testing the orchestration around a
+// Provider, such as the choice of target versions and the handling of a [goose.PartialError], not
+// the migrations themselves.
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pressly/goose/v3/database"
+)
+
+// Store is an in-memory [database.Store]. It ignores the database passed to its methods, which may
+// be nil, so its state is not rolled back with a transaction. A Store is safe for concurrent use.
+type Store struct {
+	tableName string
+
+	mu      sync.Mutex
+	created bool
+	rows    []row // in insertion order
+}
+
+type row struct {
+	version   int64
+	isApplied bool
+	timestamp time.Time
+	typ       string
+}
+
+var (
+	_ database.StoreExtender       = (*Store)(nil)
+	_ database.HistoryLister       = (*Store)(nil)
+	_ database.FutureHistoryLister = (*Store)(nil)
+	_ database.TypeLister          = (*Store)(nil)
+)
+
+// New returns a new empty Store for the version table tableName, which has not been created yet.
+func New(tableName string) *Store {
+	return &Store{tableName: tableName}
+}
+
+func (s *Store) Tablename() string {
+	return s.tableName
+}
+
+func (s *Store) CreateVersionTable(context.Context, database.DBTxConn) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.created {
+		return fmt.Errorf("version table %q already exists", s.tableName)
+	}
+	s.created = true
+	return nil
+}
+
+func (s *Store) Insert(_ context.Context, _ database.DBTxConn, req database.InsertRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkCreated(); err != nil {
+		return err
+	}
+	s.rows = append(s.rows, row{version: req.Version, isApplied: true, timestamp: time.Now().UTC(), typ: req.Type})
+	return nil
+}
+
+func (s *Store) Delete(_ context.Context, _ database.DBTxConn, version int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkCreated(); err != nil {
+		return err
+	}
+	s.rows = slices.DeleteFunc(s.rows, func(r row) bool { return r.version == version })
+	return nil
+}
+
+func (s *Store) GetMigration(_ context.Context, _ database.DBTxConn, version int64) (*database.GetMigrationResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkCreated(); err != nil {
+		return nil, err
+	}
+	for _, r := range slices.Backward(s.rows) {
+		if r.version == version {
+			return &database.GetMigrationResult{Timestamp: r.timestamp, IsApplied: r.isApplied}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d", database.ErrVersionNotFound, version)
+}
+
+func (s *Store) GetLatestVersion(context.Context, database.DBTxConn) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkCreated(); err != nil {
+		return -1, err
+	}
+	if len(s.rows) == 0 {
+		return -1, fmt.Errorf("latest %w", database.ErrVersionNotFound)
+	}
+	latest := s.rows[0].version
+	for _, r := range s.rows[1:] {
+		latest = max(latest, r.version)
+	}
+	return latest, nil
+}
+
+// ListMigrations returns the migrations in reverse insertion order, like the stores of the
+// built-in dialects.
+func (s *Store) ListMigrations(context.Context, database.DBTxConn) ([]*database.ListMigrationsResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkCreated(); err != nil {
+		return nil, err
+	}
+	migrations := make([]*database.ListMigrationsResult, 0, len(s.rows))
+	for _, r := range slices.Backward(s.rows) {
+		migrations = append(migrations, &database.ListMigrationsResult{Version: r.version, IsApplied: r.isApplied})
+	}
+	return migrations, nil
+}
+
+func (s *Store) TableExists(context.Context, database.DBTxConn) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.created, nil
+}
+
+func (s *Store) ListHistory(context.Context, database.DBTxConn) ([]*database.HistoryResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkCreated(); err != nil {
+		return nil, err
+	}
+	history := make([]*database.HistoryResult, 0, len(s.rows))
+	for _, r := range s.rows {
+		history = append(history, &database.HistoryResult{Version: r.version, IsApplied: r.isApplied, Timestamp: r.timestamp})
+	}
+	return history, nil
+}
+
+func (s *Store) ListFutureHistory(context.Context, database.DBTxConn) ([]*database.HistoryResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkCreated(); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var history []*database.HistoryResult
+	for _, r := range s.rows {
+		if r.timestamp.After(now) {
+			history = append(history, &database.HistoryResult{Version: r.version, IsApplied: r.isApplied, Timestamp: r.timestamp})
+		}
+	}
+	return history, nil
+}
+
+func (s *Store) ListTypes(context.Context, database.DBTxConn) (map[int64]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkCreated(); err != nil {
+		return nil, err
+	}
+	types := make(map[int64]string)
+	for _, r := range s.rows {
+		if r.typ != "" {
+			types[r.version] = r.typ
+		}
+	}
+	return types, nil
+}
+
+// checkCreated returns an error if the version table was not created, the way a query against a
+// missing table fails. The caller must hold s.mu.
+func (s *Store) checkCreated() error {
+	if !s.created {
+		return fmt.Errorf("version table %q does not exist", s.tableName)
+	}
+	return nil
+}
@@ -48,6 +48,9 @@ type Store interface {
 
 type InsertRequest struct {
 	Version int64
+	// Type is the type of the migration, "sql" or "go", or empty for the initial version 0 row.
+	// Stores that implement [TypeLister] record it, others may ignore it.
+	Type string
 
 	// TODO(mf): in the future, we maybe want to expand this struct so implementors can store
 	// additional information. See the following issues for more information:
@@ -1,6 +1,9 @@
 package database
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // StoreExtender is an extension of the Store interface that provides optional optimizations and
 // database-specific features. While not required by the core goose package, implementing these
@@ -31,3 +34,63 @@ type StoreExtender interface {
 	// table existence.
 	TableExists(ctx context.Context, db DBTxConn) (bool, error)
 }
+
+// HistoryLister is implemented by stores that can list the full history of the version table, as
+// used by goose history. The stores returned by [NewStore] implement it for dialects that implement
+// [dialect.HistoryLister].
+type HistoryLister interface {
+	// ListHistory retrieves every row of the version table in insertion order, oldest first,
+	// including rows with is_applied=false. Unlike ListMigrations, it also returns timestamps.
+	// Like ListMigrations, the order is by id or timestamp, so rows of a table without an id that
+	// were inserted within the resolution of the timestamp may be listed in any order.
+	//
+	// Return [errors.ErrUnsupported] if the store cannot list the version table in insertion order.
+	ListHistory(ctx context.Context, db DBTxConn) ([]*HistoryResult, error)
+}
+
+// FutureHistoryLister is implemented by stores that can list the rows of the version table recorded
+// later than the current time of the database, as used by goose doctor. The stores returned by
+// [NewStore] implement it for dialects that implement [dialect.FutureHistoryLister].
+type FutureHistoryLister interface {
+	// ListFutureHistory retrieves the rows of the version table whose timestamp is later than the
+	// current time of the database, in insertion order, oldest first.
+	//
+	// Return [errors.ErrUnsupported] if the store cannot compare timestamps with the clock of the
+	// database.
+	ListFutureHistory(ctx context.Context, db DBTxConn) ([]*HistoryResult, error)
+}
+
+// HistoryResult is a row of the version table returned by [HistoryLister.ListHistory] and
+// [FutureHistoryLister.ListFutureHistory].
+type HistoryResult struct {
+	Version   int64
+	IsApplied bool
+	// Timestamp is the time the row was inserted. It is zero if the row has no timestamp.
+	Timestamp time.Time
+}
+
+// TypeLister is implemented by stores that record the type of each migration from
+// [InsertRequest.Type], as used by goose doctor to report a migration whose type changed after it
+// was applied. The stores returned by [NewStore] implement it for dialects that implement
+// [dialect.TypeRecorder], once the version table has been upgraded to record types.
+type TypeLister interface {
+	// ListTypes returns the type last recorded for each version, keyed by version. Versions
+	// inserted without a type are not included.
+	//
+	// Return [errors.ErrUnsupported] if the store does not record migration types.
+	ListTypes(ctx context.Context, db DBTxConn) (map[int64]string, error)
+}
+
+// TableUpgrader is implemented by stores that can upgrade the layout of an existing version table,
+// e.g., the stores returned by [NewStore] for dialects that implement [dialect.TableUpgrader].
+type TableUpgrader interface {
+	// UpgradeTable detects the layout of the version table and applies the upgrades it is missing.
+	UpgradeTable(ctx context.Context, db DBTxConn) (*TableUpgradeResult, error)
+}
+
+// TableUpgradeResult is the result of [TableUpgrader.UpgradeTable].
+type TableUpgradeResult struct {
+	// From is the layout of the version table before the upgrade and To the latest layout, which
+	// the table has after the upgrade. They are equal if the table was already up to date.
+	From, To int
+}
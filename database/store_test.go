@@ -3,6 +3,7 @@ package database_test
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"path/filepath"
 	"testing"
@@ -43,6 +44,146 @@ func TestDialectStore(t *testing.T) {
 			require.Contains(t, sqliteErr.Error(), "table test_goose_db_version already exists")
 		})
 	})
+	t.Run("registered", func(t *testing.T) {
+		querier, ok := database.LookupDialect(database.DialectSQLite3)
+		require.True(t, ok)
+		const dialect database.Dialect = "sqlite3-registered"
+		database.RegisterDialect(dialect, querier)
+		require.Contains(t, database.Dialects(), dialect)
+		require.Panics(t, func() { database.RegisterDialect(dialect, querier) })
+		require.Panics(t, func() { database.RegisterDialect("", querier) })
+		require.Panics(t, func() { database.RegisterDialect("nil-querier", nil) })
+
+		db, err := sql.Open("sqlite", ":memory:")
+		require.NoError(t, err)
+		testStore(context.Background(), t, dialect, db, nil)
+	})
+	t.Run("cockroachdb retry", func(t *testing.T) {
+		store, err := database.NewStore(database.DialectCockroachDB, "foo")
+		require.NoError(t, err)
+		ctx := context.Background()
+		// Restart errors are retried until the write succeeds.
+		conn := &restartConn{restarts: 2}
+		require.NoError(t, store.Insert(ctx, conn, database.InsertRequest{Version: 1}))
+		require.Equal(t, 3, conn.calls)
+		conn = &restartConn{restarts: 1}
+		require.NoError(t, store.Delete(ctx, conn, 1))
+		require.Equal(t, 2, conn.calls)
+		// Other errors are returned right away.
+		conn = &restartConn{err: errors.New("syntax error")}
+		require.Error(t, store.Insert(ctx, conn, database.InsertRequest{Version: 1}))
+		require.Equal(t, 1, conn.calls)
+		// Writes within a transaction are not retried: the restart error aborted the whole
+		// transaction, including the migration, so it is returned to the caller, which retries the
+		// transaction.
+		txConn := &restartTxConn{}
+		db := sql.OpenDB(txConn)
+		t.Cleanup(func() { require.NoError(t, db.Close()) })
+		err = runTx(ctx, db, func(tx *sql.Tx) error {
+			return store.Insert(ctx, tx, database.InsertRequest{Version: 1})
+		})
+		var sqlErr sqlStateError
+		require.ErrorAs(t, err, &sqlErr)
+		require.Equal(t, "40001", sqlErr.SQLState())
+		require.Equal(t, 1, txConn.calls)
+	})
+	t.Run("clickhouse cluster", func(t *testing.T) {
+		store, err := database.NewClickHouseStore("foo", database.ClickHouseOptions{
+			Cluster:               "main",
+			SequentialConsistency: true,
+			Final:                 true,
+		})
+		require.NoError(t, err)
+		ctx := context.Background()
+		conn := &queryConn{}
+		require.NoError(t, store.CreateVersionTable(ctx, conn))
+		require.Contains(t, conn.query, "CREATE TABLE IF NOT EXISTS foo ON CLUSTER 'main'")
+		require.Contains(t, conn.query, "ENGINE = ReplicatedMergeTree('/clickhouse/tables/{database}/{table}', '{replica}')")
+		require.NoError(t, store.Insert(ctx, conn, database.InsertRequest{Version: 1}))
+		require.Contains(t, conn.query, "insert_quorum = 'auto'")
+		_, err = store.ListMigrations(ctx, conn)
+		require.Error(t, err)
+		require.Contains(t, conn.query, "FROM foo FINAL")
+		require.Contains(t, conn.query, "SETTINGS select_sequential_consistency = 1")
+		// The zero options match the registered dialect.
+		store, err = database.NewClickHouseStore("foo", database.ClickHouseOptions{})
+		require.NoError(t, err)
+		require.NoError(t, store.CreateVersionTable(ctx, conn))
+		require.Contains(t, conn.query, "ENGINE = MergeTree()")
+		require.NotContains(t, conn.query, "ON CLUSTER")
+	})
+	t.Run("create schema", func(t *testing.T) {
+		store, err := database.NewStore(database.DialectPostgres, "migrations.foo",
+			database.WithCreateSchema("app"))
+		require.NoError(t, err)
+		ctx := context.Background()
+		conn := &queryConn{}
+		require.NoError(t, store.CreateVersionTable(ctx, conn))
+		require.GreaterOrEqual(t, len(conn.execs), 2)
+		require.Equal(t, "CREATE SCHEMA IF NOT EXISTS migrations AUTHORIZATION app", conn.execs[0])
+		require.Contains(t, conn.execs[1], "CREATE TABLE migrations.foo")
+		// An unqualified table has no schema to create.
+		store, err = database.NewStore(database.DialectPostgres, "foo", database.WithCreateSchema(""))
+		require.NoError(t, err)
+		conn = &queryConn{}
+		require.NoError(t, store.CreateVersionTable(ctx, conn))
+		for _, q := range conn.execs {
+			require.NotContains(t, q, "CREATE SCHEMA")
+		}
+		// SQLite has no schemas.
+		_, err = database.NewStore(database.DialectSQLite3, "foo", database.WithCreateSchema(""))
+		require.Error(t, err)
+	})
+	t.Run("UpgradeTable", func(t *testing.T) {
+		db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "sql_embed.db"))
+		require.NoError(t, err)
+		store, err := database.NewStore(database.DialectSQLite3, "foo")
+		require.NoError(t, err)
+		upgrader, ok := store.(database.TableUpgrader)
+		require.True(t, ok)
+		ctx := context.Background()
+		indexExists := func(t *testing.T) bool {
+			t.Helper()
+			var n int
+			err := db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type='index' AND name='foo_version_id_idx'`).Scan(&n)
+			require.NoError(t, err)
+			return n == 1
+		}
+		// A table created by an older goose release has the original layout.
+		_, err = db.Exec(`CREATE TABLE foo (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			version_id INTEGER NOT NULL,
+			is_applied INTEGER NOT NULL,
+			tstamp TIMESTAMP DEFAULT (datetime('now'))
+		)`)
+		require.NoError(t, err)
+		require.False(t, indexExists(t))
+		// Without the migration_type column, types are not recorded.
+		lister, ok := store.(database.TypeLister)
+		require.True(t, ok)
+		require.NoError(t, store.Insert(ctx, db, database.InsertRequest{Version: 1, Type: "sql"}))
+		_, err = lister.ListTypes(ctx, db)
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+		res, err := upgrader.UpgradeTable(ctx, db)
+		require.NoError(t, err)
+		require.Equal(t, &database.TableUpgradeResult{From: 1, To: 3}, res)
+		require.True(t, indexExists(t))
+		require.NoError(t, store.Insert(ctx, db, database.InsertRequest{Version: 2, Type: "go"}))
+		types, err := lister.ListTypes(ctx, db)
+		require.NoError(t, err)
+		require.Equal(t, map[int64]string{2: "go"}, types)
+		// Upgrading again is a no-op.
+		res, err = upgrader.UpgradeTable(ctx, db)
+		require.NoError(t, err)
+		require.Equal(t, &database.TableUpgradeResult{From: 3, To: 3}, res)
+		// New tables are created with the latest layout.
+		store, err = database.NewStore(database.DialectSQLite3, "bar")
+		require.NoError(t, err)
+		require.NoError(t, store.CreateVersionTable(ctx, db))
+		res, err = store.(database.TableUpgrader).UpgradeTable(ctx, db)
+		require.NoError(t, err)
+		require.Equal(t, &database.TableUpgradeResult{From: 3, To: 3}, res)
+	})
 	t.Run("ListMigrations", func(t *testing.T) {
 		dir := t.TempDir()
 		db, err := sql.Open("sqlite", filepath.Join(dir, "sql_embed.db"))
@@ -65,6 +206,26 @@ func TestDialectStore(t *testing.T) {
 		require.EqualValues(t, 3, res[1].Version)
 		require.EqualValues(t, 1, res[2].Version)
 	})
+	t.Run("ListHistory", func(t *testing.T) {
+		db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "sql_embed.db"))
+		require.NoError(t, err)
+		store, err := database.NewStore(database.DialectSQLite3, "foo")
+		require.NoError(t, err)
+		ctx := context.Background()
+		require.NoError(t, store.CreateVersionTable(ctx, db))
+		require.NoError(t, store.Insert(ctx, db, database.InsertRequest{Version: 3}))
+		require.NoError(t, store.Insert(ctx, db, database.InsertRequest{Version: 1}))
+		lister, ok := store.(database.HistoryLister)
+		require.True(t, ok)
+		res, err := lister.ListHistory(ctx, db)
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		// Check rows are in insertion order: [3, 1]
+		require.EqualValues(t, 3, res[0].Version)
+		require.EqualValues(t, 1, res[1].Version)
+		require.True(t, res[0].IsApplied)
+		require.False(t, res[0].Timestamp.IsZero())
+	})
 }
 
 // testStore tests various store operations.
@@ -251,3 +412,70 @@ func runConn(ctx context.Context, db *sql.DB, fn func(*sql.Conn) error) (retErr
 	}
 	return conn.Close()
 }
+
+// restartConn fails its first restarts writes with a CockroachDB transaction restart error, and then
+// with err.
+type restartConn struct {
+	database.DBTxConn
+	restarts int
+	err      error
+	calls    int
+}
+
+func (c *restartConn) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	c.calls++
+	if c.calls <= c.restarts {
+		return nil, sqlStateError("40001")
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+// restartTxConn is a driver connection, and its own connector, that fails every write with a
+// CockroachDB transaction restart error. It provides a *sql.Tx for tests that need one.
+type restartTxConn struct {
+	calls int
+}
+
+var (
+	_ driver.Connector     = (*restartTxConn)(nil)
+	_ driver.ExecerContext = (*restartTxConn)(nil)
+)
+
+func (c *restartTxConn) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (c *restartTxConn) Driver() driver.Driver                        { return nil }
+func (c *restartTxConn) Prepare(string) (driver.Stmt, error)          { return nil, errors.ErrUnsupported }
+func (c *restartTxConn) Close() error                                 { return nil }
+func (c *restartTxConn) Begin() (driver.Tx, error)                    { return c, nil }
+func (c *restartTxConn) Commit() error                                { return nil }
+func (c *restartTxConn) Rollback() error                              { return nil }
+
+func (c *restartTxConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	c.calls++
+	return nil, sqlStateError("40001")
+}
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "SQLSTATE " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+// queryConn records the last query it was given, and every statement it executed.
+type queryConn struct {
+	database.DBTxConn
+	query string
+	execs []string
+}
+
+func (c *queryConn) ExecContext(_ context.Context, query string, _ ...any) (sql.Result, error) {
+	c.query = query
+	c.execs = append(c.execs, query)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *queryConn) QueryContext(_ context.Context, query string, _ ...any) (*sql.Rows, error) {
+	c.query = query
+	return nil, errors.New("not implemented")
+}
@@ -0,0 +1,230 @@
+// Package storetest provides a conformance suite for [database.Store] and [dialect.Querier]
+// implementations, to check a custom store honors the contracts documented on the Store interface.
+package storetest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pressly/goose/v3/database"
+	"github.com/pressly/goose/v3/database/dialect"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+// TestQuerier runs [TestStore] against the store returned by [database.NewStoreFromQuerier] for
+// querier.
+func TestQuerier(t *testing.T, db *sql.DB, querier dialect.Querier) {
+	t.Helper()
+	TestStore(t, db, func(t *testing.T, tableName string) database.Store {
+		t.Helper()
+		store, err := database.NewStoreFromQuerier(tableName, querier)
+		require.NoError(t, err)
+		return store
+	})
+}
+
+// TestStore is a reusable test helper that runs a behavioral suite against the stores returned by
+// newStore, using db. Every subtest asks newStore for a store of its own version table, named
+// goose_storetest_*, so db should be a disposable database.
+//
+// The suite verifies the Store contract:
+//
+//  1. CreateVersionTable creates the table, and calling it again does not lose rows
+//  2. GetMigration and GetLatestVersion return [database.ErrVersionNotFound] for missing versions
+//  3. ListMigrations returns every row, and an empty slice without rows
+//  4. Delete removes a version
+//  5. Concurrent inserts are all recorded
+//
+// The optional TableExists method of [database.StoreExtender], ListHistory method of
+// [database.HistoryLister], ListFutureHistory method of [database.FutureHistoryLister] and
+// ListTypes method of [database.TypeLister] are verified if the store implements them and does not
+// return [errors.ErrUnsupported].
+//
+// The order of the rows is not checked beyond the contract of the Store interface: stores may sort
+// rows by a timestamp, whose resolution may be too coarse to tell the inserts of the suite apart.
+func TestStore(
+	t *testing.T,
+	db *sql.DB,
+	newStore func(t *testing.T, tableName string) database.Store,
+) {
+	t.Helper()
+	ctx := context.Background()
+
+	var n int
+	// create returns a store whose version table was created.
+	create := func(t *testing.T) database.Store {
+		t.Helper()
+		n++
+		store := newStore(t, fmt.Sprintf("goose_storetest_%d", n))
+		require.NotEmpty(t, store.Tablename(), "store must have a table name")
+		require.NoError(t, store.CreateVersionTable(ctx, db))
+		return store
+	}
+	insert := func(t *testing.T, store database.Store, versions ...int64) {
+		t.Helper()
+		for _, v := range versions {
+			require.NoError(t, store.Insert(ctx, db, database.InsertRequest{Version: v}), "insert version %d", v)
+		}
+	}
+	listVersions := func(t *testing.T, store database.Store) []int64 {
+		t.Helper()
+		res, err := store.ListMigrations(ctx, db)
+		require.NoError(t, err)
+		versions := make([]int64, 0, len(res))
+		for _, m := range res {
+			require.True(t, m.IsApplied, "version %d must be applied", m.Version)
+			versions = append(versions, m.Version)
+		}
+		return versions
+	}
+
+	t.Run("CreateVersionTable", func(t *testing.T) {
+		n++
+		store := newStore(t, fmt.Sprintf("goose_storetest_%d", n))
+		if exists, ok := tableExists(ctx, t, store, db); ok {
+			require.False(t, exists, "TableExists must be false before the table is created")
+		}
+		require.NoError(t, store.CreateVersionTable(ctx, db))
+		if exists, ok := tableExists(ctx, t, store, db); ok {
+			require.True(t, exists, "TableExists must be true after the table is created")
+		}
+		insert(t, store, 0)
+		// Creating the table again may fail, like CREATE TABLE, or do nothing, like CREATE TABLE IF
+		// NOT EXISTS, but must keep the existing rows.
+		_ = store.CreateVersionTable(ctx, db)
+		require.Equal(t, []int64{0}, listVersions(t, store))
+	})
+	t.Run("ErrVersionNotFound", func(t *testing.T) {
+		store := create(t)
+		_, err := store.GetLatestVersion(ctx, db)
+		require.ErrorIs(t, err, database.ErrVersionNotFound, "GetLatestVersion on an empty table")
+		_, err = store.GetMigration(ctx, db, 1)
+		require.ErrorIs(t, err, database.ErrVersionNotFound, "GetMigration of a missing version")
+		insert(t, store, 0)
+		_, err = store.GetMigration(ctx, db, 1)
+		require.ErrorIs(t, err, database.ErrVersionNotFound, "GetMigration of a missing version")
+		latest, err := store.GetLatestVersion(ctx, db)
+		require.NoError(t, err)
+		require.EqualValues(t, 0, latest)
+	})
+	t.Run("GetMigration", func(t *testing.T) {
+		store := create(t)
+		insert(t, store, 0, 1)
+		res, err := store.GetMigration(ctx, db, 1)
+		require.NoError(t, err)
+		require.True(t, res.IsApplied)
+		require.False(t, res.Timestamp.IsZero(), "GetMigration must return the time the version was inserted")
+	})
+	t.Run("ListMigrations", func(t *testing.T) {
+		store := create(t)
+		res, err := store.ListMigrations(ctx, db)
+		require.NoError(t, err)
+		require.Empty(t, res, "ListMigrations on an empty table")
+		insert(t, store, 0, 1, 3, 2)
+		require.ElementsMatch(t, []int64{0, 1, 3, 2}, listVersions(t, store))
+		latest, err := store.GetLatestVersion(ctx, db)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, latest, "GetLatestVersion must return the highest version")
+	})
+	t.Run("Delete", func(t *testing.T) {
+		store := create(t)
+		insert(t, store, 0, 1, 2)
+		require.NoError(t, store.Delete(ctx, db, 2))
+		require.ElementsMatch(t, []int64{0, 1}, listVersions(t, store))
+		_, err := store.GetMigration(ctx, db, 2)
+		require.ErrorIs(t, err, database.ErrVersionNotFound, "GetMigration of a deleted version")
+		latest, err := store.GetLatestVersion(ctx, db)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, latest)
+	})
+	t.Run("ListHistory", func(t *testing.T) {
+		store := create(t)
+		lister, ok := store.(database.HistoryLister)
+		if !ok {
+			t.Skip("store does not implement ListHistory")
+		}
+		insert(t, store, 0, 2, 1)
+		history, err := lister.ListHistory(ctx, db)
+		if errors.Is(err, errors.ErrUnsupported) {
+			t.Skip("store does not support ListHistory")
+		}
+		require.NoError(t, err)
+		versions := make([]int64, 0, len(history))
+		for i, h := range history {
+			require.True(t, h.IsApplied)
+			// Rows are listed oldest first, which may only be observable from their timestamps.
+			if i > 0 && !h.Timestamp.IsZero() {
+				require.False(t, h.Timestamp.Before(history[i-1].Timestamp), "ListHistory must list rows oldest first")
+			}
+			versions = append(versions, h.Version)
+		}
+		require.ElementsMatch(t, []int64{0, 2, 1}, versions)
+	})
+	t.Run("ListFutureHistory", func(t *testing.T) {
+		store := create(t)
+		lister, ok := store.(database.FutureHistoryLister)
+		if !ok {
+			t.Skip("store does not implement ListFutureHistory")
+		}
+		insert(t, store, 0, 1)
+		future, err := lister.ListFutureHistory(ctx, db)
+		if errors.Is(err, errors.ErrUnsupported) {
+			t.Skip("store does not support ListFutureHistory")
+		}
+		require.NoError(t, err)
+		// Rows are recorded with the clock of the database, so none of them is in the future.
+		require.Empty(t, future)
+	})
+	t.Run("ListTypes", func(t *testing.T) {
+		store := create(t)
+		lister, ok := store.(database.TypeLister)
+		if !ok {
+			t.Skip("store does not implement ListTypes")
+		}
+		insert(t, store, 0)
+		for v, typ := range map[int64]string{1: "sql", 2: "go"} {
+			require.NoError(t, store.Insert(ctx, db, database.InsertRequest{Version: v, Type: typ}))
+		}
+		types, err := lister.ListTypes(ctx, db)
+		if errors.Is(err, errors.ErrUnsupported) {
+			t.Skip("store does not support ListTypes")
+		}
+		require.NoError(t, err)
+		// The initial version is inserted without a type.
+		require.Equal(t, map[int64]string{1: "sql", 2: "go"}, types)
+	})
+	t.Run("ConcurrentInserts", func(t *testing.T) {
+		store := create(t)
+		const count = 10
+		var g errgroup.Group
+		for i := range count {
+			g.Go(func() error {
+				return store.Insert(ctx, db, database.InsertRequest{Version: int64(i + 1)})
+			})
+		}
+		require.NoError(t, g.Wait())
+		require.ElementsMatch(t, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, listVersions(t, store))
+	})
+}
+
+// tableExists calls the optional TableExists method of store. It returns false for ok if the store
+// does not support it.
+func tableExists(ctx context.Context, t *testing.T, store database.Store, db *sql.DB) (exists, ok bool) {
+	t.Helper()
+	e, ok := store.(interface {
+		TableExists(ctx context.Context, db database.DBTxConn) (bool, error)
+	})
+	if !ok {
+		return false, false
+	}
+	exists, err := e.TableExists(ctx, db)
+	if errors.Is(err, errors.ErrUnsupported) {
+		return false, false
+	}
+	require.NoError(t, err)
+	return exists, true
+}
@@ -0,0 +1,34 @@
+package storetest_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/pressly/goose/v3/database"
+	"github.com/pressly/goose/v3/database/memstore"
+	"github.com/pressly/goose/v3/database/storetest"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func TestSQLite3(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "storetest.db"))
+	require.NoError(t, err)
+	// SQLite allows a single writer, serialize the concurrent inserts on one connection.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	querier, ok := database.LookupDialect(database.DialectSQLite3)
+	require.True(t, ok)
+	storetest.TestQuerier(t, db, querier)
+}
+
+func TestMemstore(t *testing.T) {
+	t.Parallel()
+
+	storetest.TestStore(t, memstore.OpenDB(), func(t *testing.T, tableName string) database.Store {
+		return memstore.New(tableName)
+	})
+}
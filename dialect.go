@@ -2,27 +2,33 @@ package goose
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/pressly/goose/v3/database"
 	"github.com/pressly/goose/v3/internal/legacystore"
+	"github.com/pressly/goose/v3/internal/sqlparser"
 )
 
 // Dialect is the type of database dialect. It is an alias for [database.Dialect].
 type Dialect = database.Dialect
 
 const (
-	DialectCustom     Dialect = database.DialectCustom
-	DialectClickHouse Dialect = database.DialectClickHouse
-	DialectMSSQL      Dialect = database.DialectMSSQL
-	DialectMySQL      Dialect = database.DialectMySQL
-	DialectPostgres   Dialect = database.DialectPostgres
-	DialectRedshift   Dialect = database.DialectRedshift
-	DialectSQLite3    Dialect = database.DialectSQLite3
-	DialectSpanner    Dialect = database.DialectSpanner
-	DialectStarrocks  Dialect = database.DialectStarrocks
-	DialectTiDB       Dialect = database.DialectTiDB
-	DialectTurso      Dialect = database.DialectTurso
-	DialectYdB        Dialect = database.DialectYdB
+	DialectCustom      Dialect = database.DialectCustom
+	DialectClickHouse  Dialect = database.DialectClickHouse
+	DialectCockroachDB Dialect = database.DialectCockroachDB
+	DialectDuckDB      Dialect = database.DialectDuckDB
+	DialectMSSQL       Dialect = database.DialectMSSQL
+	DialectMySQL       Dialect = database.DialectMySQL
+	DialectOracle      Dialect = database.DialectOracle
+	DialectPostgres    Dialect = database.DialectPostgres
+	DialectRedshift    Dialect = database.DialectRedshift
+	DialectSQLite3     Dialect = database.DialectSQLite3
+	DialectSpanner     Dialect = database.DialectSpanner
+	DialectStarrocks   Dialect = database.DialectStarrocks
+	DialectTiDB        Dialect = database.DialectTiDB
+	DialectTurso       Dialect = database.DialectTurso
+	DialectYdB         Dialect = database.DialectYdB
 
 	// Dialects only available to the [Provider].
 	DialectAuroraDSQL Dialect = database.DialectAuroraDSQL
@@ -35,40 +41,108 @@ func init() {
 	store, _ = legacystore.NewStore(DialectPostgres)
 }
 
-var store legacystore.Store
+var (
+	store        legacystore.Store
+	storeDialect = DialectPostgres
+)
 
-// SetDialect sets the dialect to use for the goose package.
+// SetDialect sets the dialect to use for the goose package. s is a driver name, see
+// [RegisterDriver].
 func SetDialect(s string) error {
-	var d Dialect
-	switch s {
-	case "postgres", "pgx":
-		d = DialectPostgres
-	case "mysql":
-		d = DialectMySQL
-	case "sqlite3", "sqlite":
-		d = DialectSQLite3
-	case "spanner":
-		d = DialectSpanner
-	case "mssql", "azuresql", "sqlserver":
-		d = DialectMSSQL
-	case "redshift":
-		d = DialectRedshift
-	case "tidb":
-		d = DialectTiDB
-	case "clickhouse":
-		d = DialectClickHouse
-	case "vertica":
-		d = DialectVertica
-	case "ydb":
-		d = DialectYdB
-	case "turso":
-		d = DialectTurso
-	case "starrocks":
-		d = DialectStarrocks
-	default:
+	d, _, ok := LookupDriver(s)
+	if !ok {
 		return fmt.Errorf("%q: unknown dialect", s)
 	}
-	var err error
-	store, err = legacystore.NewStore(d)
-	return err
+	newStore, err := legacystore.NewStore(d)
+	if err != nil {
+		return err
+	}
+	store, storeDialect = newStore, d
+	return nil
+}
+
+// sqlParseOptions returns the options to parse the SQL migrations of a dialect with.
+func sqlParseOptions(d Dialect) []sqlparser.ParseOption {
+	if d == DialectOracle {
+		return []sqlparser.ParseOption{sqlparser.WithPLSQL()}
+	}
+	return nil
+}
+
+// clickhouseParseOption returns the variables of ClickHouse migrations for the ENVSUB annotation.
+func clickhouseParseOption(cluster string) sqlparser.ParseOption {
+	var onCluster string
+	if cluster != "" {
+		onCluster = "ON CLUSTER '" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(cluster) + "'"
+	}
+	return sqlparser.WithEnv(map[string]string{
+		"GOOSE_CLUSTER":    cluster,
+		"GOOSE_ON_CLUSTER": onCluster,
+	})
+}
+
+// driver is a driver name accepted by SetDialect, OpenDBWithDriver and the goose CLI.
+type driver struct {
+	dialect   Dialect
+	sqlDriver string
+}
+
+var (
+	driversMu sync.RWMutex
+	drivers   = map[string]driver{
+		"postgres":    {DialectPostgres, "pgx"},
+		"pgx":         {DialectPostgres, "pgx"},
+		"cockroachdb": {DialectCockroachDB, "pgx"},
+		"mysql":       {DialectMySQL, "mysql"},
+		"oracle":      {DialectOracle, "oracle"},
+		"sqlite3":     {DialectSQLite3, "sqlite"},
+		"sqlite":      {DialectSQLite3, "sqlite"},
+		"spanner":     {DialectSpanner, "spanner"},
+		"mssql":       {DialectMSSQL, "sqlserver"},
+		"azuresql":    {DialectMSSQL, "azuresql"},
+		"sqlserver":   {DialectMSSQL, "sqlserver"},
+		"redshift":    {DialectRedshift, "pgx"},
+		"tidb":        {DialectTiDB, "mysql"},
+		"clickhouse":  {DialectClickHouse, "clickhouse"},
+		"duckdb":      {DialectDuckDB, "duckdb"},
+		"vertica":     {DialectVertica, "vertica"},
+		"ydb":         {DialectYdB, "ydb"},
+		"turso":       {DialectTurso, "libsql"},
+		"starrocks":   {DialectStarrocks, "mysql"},
+	}
+)
+
+// RegisterDriver makes name usable as the driver of [SetDialect], [OpenDBWithDriver] and the goose
+// CLI. The name selects dialect, which is either built in or added with
+// [database.RegisterDialect], and OpenDBWithDriver opens the database with sqlDriver, the name the
+// database/sql driver is registered with.
+//
+// For example, a package adding support for an in-house database registers its dialect and a
+// driver name in its init function:
+//
+//	func init() {
+//		database.RegisterDialect("acmedb", querier{})
+//		goose.RegisterDriver("acmedb", "acmedb", "acme")
+//	}
+//
+// If RegisterDriver is called twice with the same name, or if any argument is empty, it panics.
+func RegisterDriver(name string, dialect Dialect, sqlDriver string) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	if name == "" || dialect == DialectCustom || sqlDriver == "" {
+		panic("goose: RegisterDriver name, dialect and sqlDriver must not be empty")
+	}
+	if _, dup := drivers[name]; dup {
+		panic("goose: RegisterDriver called twice for driver " + name)
+	}
+	drivers[name] = driver{dialect: dialect, sqlDriver: sqlDriver}
+}
+
+// LookupDriver returns the dialect and the database/sql driver name of a built-in or registered
+// driver name, and whether the driver name exists.
+func LookupDriver(name string) (dialect Dialect, sqlDriver string, ok bool) {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	d, ok := drivers[name]
+	return d.dialect, d.sqlDriver, ok
 }
@@ -1,9 +1,12 @@
 package goose
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -52,3 +55,141 @@ func Fix(dir string) error {
 
 	return nil
 }
+
+// RenamedMigration is a migration file renamed by [FixRebaseOnto].
+type RenamedMigration struct {
+	OldVersion int64
+	NewVersion int64
+	OldPath    string
+	NewPath    string
+}
+
+// FixRebaseOnto renumbers new migrations so they follow onto, the highest version that has already
+// been applied or committed, e.g., on the main branch. This resolves duplicate and out-of-order
+// versions introduced by branches that created migrations concurrently.
+//
+// Migrations with a version greater than onto are new. A version alone cannot tell which of two
+// colliding files is new, so the file names of new migrations at or below onto must be passed in
+// newFiles. Every version at or below onto that is still used by several files is reported as an
+// error. New migrations keep their relative order and are numbered sequentially from onto+1; all
+// other migrations are never renamed. The renames are returned in order, and are empty if nothing
+// needed to change. If a rename fails, the files are restored to their old names.
+func FixRebaseOnto(dir string, onto int64, newFiles ...string) ([]RenamedMigration, error) {
+	if onto < 0 {
+		return nil, fmt.Errorf("invalid version to rebase onto: %d", onto)
+	}
+	var paths []string
+	for _, pattern := range []string{"*.sql", "*.go"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	isNew := make(map[string]bool, len(newFiles))
+	for _, name := range newFiles {
+		isNew[filepath.Base(name)] = true
+	}
+	type file struct {
+		path    string
+		version int64
+	}
+	var committed, pending []file
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		version, err := NumericComponent(path)
+		if err != nil {
+			continue
+		}
+		name := filepath.Base(path)
+		if version > onto || isNew[name] {
+			pending = append(pending, file{path: path, version: version})
+			delete(isNew, name)
+		} else {
+			committed = append(committed, file{path: path, version: version})
+		}
+	}
+	for _, name := range newFiles {
+		if isNew[filepath.Base(name)] {
+			return nil, fmt.Errorf("new migration not found: %s", name)
+		}
+	}
+	byVersion := func(a, b file) int {
+		return cmp.Or(cmp.Compare(a.version, b.version), cmp.Compare(a.path, b.path))
+	}
+	slices.SortFunc(committed, byVersion)
+	slices.SortFunc(pending, byVersion)
+	// A version at or below onto used by several files is a collision with a new migration that
+	// was not named. Report all of them, rather than treating the new one as committed.
+	var duplicates []error
+	for i := 1; i < len(committed); i++ {
+		if committed[i].version == committed[i-1].version {
+			duplicates = append(duplicates, fmt.Errorf("duplicate version %d: %s and %s, pass the file name of the new one",
+				committed[i].version, filepath.Base(committed[i-1].path), filepath.Base(committed[i].path)))
+		}
+	}
+	if len(duplicates) > 0 {
+		return nil, errors.Join(duplicates...)
+	}
+
+	var renames []RenamedMigration
+	renamed := make(map[string]bool)
+	for i, f := range pending {
+		version := onto + int64(i) + 1
+		if version == f.version {
+			continue
+		}
+		base := filepath.Base(f.path)
+		_, rest, _ := strings.Cut(base, "_")
+		renames = append(renames, RenamedMigration{
+			OldVersion: f.version,
+			NewVersion: version,
+			OldPath:    f.path,
+			NewPath:    filepath.Join(filepath.Dir(f.path), fmt.Sprintf(seqVersionTemplate, version)+"_"+rest),
+		})
+		renamed[f.path] = true
+	}
+	for _, r := range renames {
+		if _, err := os.Stat(r.NewPath); err == nil && !renamed[r.NewPath] {
+			return nil, fmt.Errorf("failed to rename %s: %s already exists", filepath.Base(r.OldPath), r.NewPath)
+		}
+	}
+	// Rename in two steps, so a new name may be the old name of another renamed file. If a rename
+	// fails, the completed ones are undone.
+	for i, r := range renames {
+		if err := renameFile(r.OldPath, r.OldPath+".rebase"); err != nil {
+			return nil, errors.Join(err, undoRebase(renames[:i], nil))
+		}
+	}
+	for i, r := range renames {
+		if err := renameFile(r.OldPath+".rebase", r.NewPath); err != nil {
+			return nil, errors.Join(err, undoRebase(renames, renames[:i]))
+		}
+	}
+	for _, r := range renames {
+		log.Printf("RENAMED %s => %s", filepath.Base(r.OldPath), filepath.Base(r.NewPath))
+	}
+	return renames, nil
+}
+
+// renameFile renames files for FixRebaseOnto, replaced in tests to simulate failures.
+var renameFile = os.Rename
+
+// undoRebase restores the old names of the staged renames of FixRebaseOnto, of which moved already
+// have their new name.
+func undoRebase(staged, moved []RenamedMigration) error {
+	var errs []error
+	for _, r := range moved {
+		if err := renameFile(r.NewPath, r.OldPath+".rebase"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, r := range staged {
+		if err := renameFile(r.OldPath+".rebase", r.OldPath); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
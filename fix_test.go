@@ -1,12 +1,16 @@
 package goose
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestFix(t *testing.T) {
@@ -80,3 +84,95 @@ func TestFix(t *testing.T) {
 		}
 	}
 }
+
+func TestFixRebaseOnto(t *testing.T) {
+	t.Parallel()
+
+	create := func(t *testing.T, names ...string) string {
+		t.Helper()
+		dir := t.TempDir()
+		for _, name := range names {
+			writeFile(t, filepath.Join(dir, name), "-- +goose Up\n")
+		}
+		return dir
+	}
+	list := func(t *testing.T, dir string) []string {
+		t.Helper()
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		return names
+	}
+
+	t.Run("after_onto", func(t *testing.T) {
+		// The branch added 00006 and 00007, which already follow 00005 on main.
+		dir := create(t, "00001_a.sql", "00002_b.sql", "00003_c.sql", "00006_d.sql", "00007_e.go")
+		renames, err := FixRebaseOnto(dir, 5)
+		require.NoError(t, err)
+		require.Empty(t, renames)
+
+		// main has since reached 00007 with other files, not merged here yet.
+		renames, err = FixRebaseOnto(dir, 7, "00006_d.sql", "00007_e.go")
+		require.NoError(t, err)
+		require.Len(t, renames, 2)
+		require.Equal(t, RenamedMigration{
+			OldVersion: 6,
+			NewVersion: 8,
+			OldPath:    filepath.Join(dir, "00006_d.sql"),
+			NewPath:    filepath.Join(dir, "00008_d.sql"),
+		}, renames[0])
+		require.Equal(t, []string{"00001_a.sql", "00002_b.sql", "00003_c.sql", "00008_d.sql", "00009_e.go"}, list(t, dir))
+	})
+	t.Run("duplicates", func(t *testing.T) {
+		dir := create(t, "00001_a.sql", "00002_main.sql", "00002_branch.sql", "00003_branch.sql")
+		_, err := FixRebaseOnto(dir, 2)
+		require.EqualError(t, err, "duplicate version 2: 00002_branch.sql and 00002_main.sql, pass the file name of the new one")
+
+		renames, err := FixRebaseOnto(dir, 2, "00002_branch.sql")
+		require.NoError(t, err)
+		require.Len(t, renames, 2)
+		require.Equal(t, []string{"00001_a.sql", "00002_main.sql", "00003_branch.sql", "00004_branch.sql"}, list(t, dir))
+		// The old 00003_branch.sql moved to 00004, and 00002_branch.sql took its name.
+		require.Equal(t, int64(2), renames[0].OldVersion)
+		require.Equal(t, int64(3), renames[0].NewVersion)
+	})
+	t.Run("all_duplicates", func(t *testing.T) {
+		dir := create(t, "00001_a.sql", "00002_main.sql", "00002_branch.sql", "00003_main.sql", "00003_branch.sql")
+		_, err := FixRebaseOnto(dir, 3)
+		require.EqualError(t, err, "duplicate version 2: 00002_branch.sql and 00002_main.sql, pass the file name of the new one\n"+
+			"duplicate version 3: 00003_branch.sql and 00003_main.sql, pass the file name of the new one")
+	})
+	t.Run("undo_on_error", func(t *testing.T) {
+		names := []string{"00001_a.sql", "00002_branch.sql", "00002_main.sql", "00003_branch.sql"}
+		t.Cleanup(func() { renameFile = os.Rename })
+		// Fail in the first and in the second renaming step.
+		for _, failOn := range []string{"00003_branch.sql.rebase", "00004_branch.sql"} {
+			dir := create(t, names...)
+			renameFile = func(oldpath, newpath string) error {
+				if filepath.Base(newpath) == failOn {
+					return errors.New("rename failed")
+				}
+				return os.Rename(oldpath, newpath)
+			}
+			_, err := FixRebaseOnto(dir, 2, "00002_branch.sql")
+			require.EqualError(t, err, "rename failed")
+			require.Equal(t, names, list(t, dir), failOn)
+		}
+	})
+	t.Run("timestamps", func(t *testing.T) {
+		dir := create(t, "20240101000000_a.sql", "20240301000000_main.sql", "20240201000000_branch.sql")
+		_, err := FixRebaseOnto(dir, 20240301000000, "20240201000000_branch.sql")
+		require.NoError(t, err)
+		require.Equal(t, []string{"20240101000000_a.sql", "20240301000000_main.sql", "20240301000001_branch.sql"}, list(t, dir))
+	})
+	t.Run("errors", func(t *testing.T) {
+		dir := create(t, "00001_a.sql")
+		_, err := FixRebaseOnto(dir, 1, "00002_missing.sql")
+		require.EqualError(t, err, "new migration not found: 00002_missing.sql")
+		_, err = FixRebaseOnto(dir, -1)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,110 @@
+package goose
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pressly/goose/v3/internal/autodown"
+	"github.com/pressly/goose/v3/internal/sqlparser"
+)
+
+// placeholderDown is the down statement written by the default SQL migration template. A down
+// section that only contains it is considered empty.
+const placeholderDown = "SELECT 'down SQL query';"
+
+// GenDown generates the down section of the SQL migration with the given version from its up
+// statements. Recognized reversible DDL (CREATE TABLE/INDEX/VIEW, ALTER TABLE ... ADD COLUMN and
+// renames) is inverted; every other statement produces a "-- TODO(goose):" comment that must be
+// replaced by hand.
+//
+// The down section must be empty, or only contain the placeholder written by [Create].
+func GenDown(dir string, version int64) error {
+	// always use osFS here because it's modifying operation
+	migrations, err := collectMigrationsFS(osFS{}, dir, minVersion, maxVersion, registeredGoMigrations)
+	if err != nil {
+		return err
+	}
+	m, err := migrations.Current(version)
+	if err != nil {
+		return fmt.Errorf("failed to find migration %d: %w", version, err)
+	}
+	if filepath.Ext(m.Source) != ".sql" {
+		return fmt.Errorf("gen-down only supports SQL migrations: %s", filepath.Base(m.Source))
+	}
+	data, err := os.ReadFile(m.Source)
+	if err != nil {
+		return err
+	}
+	out, err := genDown(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(m.Source), err)
+	}
+	if err := os.WriteFile(m.Source, out, 0644); err != nil {
+		return err
+	}
+	log.Printf("GENERATED down migration in %s", filepath.Base(m.Source))
+	return nil
+}
+
+func genDown(data []byte) ([]byte, error) {
+	parsed, err := sqlparser.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed.Up) == 0 {
+		return nil, errors.New("no up statements to generate a down migration from")
+	}
+	lines := strings.Split(string(data), "\n")
+	// Drop the template placeholder, the only down statement we are allowed to replace.
+	for _, stmt := range parsed.Down {
+		if strings.TrimSpace(stmt.SQL) != placeholderDown {
+			return nil, errors.New("down section is not empty")
+		}
+		for i := stmt.StartLine - 1; i < stmt.EndLine; i++ {
+			lines[i] = ""
+		}
+	}
+	up := make([]string, 0, len(parsed.Up))
+	for _, stmt := range parsed.Up {
+		up = append(up, stmt.SQL)
+	}
+	generated := autodown.Generate(up)
+
+	downLine := -1
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "--") {
+			continue
+		}
+		if a, err := sqlparser.ExtractAnnotation(line); err == nil && a == sqlparser.AnnotationDown {
+			downLine = i
+			break
+		}
+	}
+	var out []string
+	if downLine < 0 {
+		out = append(strings.Split(strings.TrimRight(strings.Join(lines, "\n"), "\n"), "\n"),
+			"",
+			"-- +goose Down",
+		)
+		out = append(out, generated...)
+	} else {
+		out = append(out, lines[:downLine+1]...)
+		out = append(out, generated...)
+		// The down section held at most the placeholder, so any StatementBegin/End around it is
+		// left empty. Generated statements are single statements and do not need it.
+		for _, line := range lines[downLine+1:] {
+			if strings.HasPrefix(line, "--") {
+				if a, err := sqlparser.ExtractAnnotation(line); err == nil &&
+					(a == sqlparser.AnnotationStatementBegin || a == sqlparser.AnnotationStatementEnd) {
+					continue
+				}
+			}
+			out = append(out, line)
+		}
+	}
+	return []byte(strings.TrimRight(strings.Join(out, "\n"), "\n") + "\n"), nil
+}
@@ -0,0 +1,97 @@
+package goose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenDown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("replace_placeholder", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "00001_users.sql")
+		writeFile(t, path, `-- +goose Up
+CREATE TABLE users (id int);
+CREATE INDEX users_idx ON users (id);
+UPDATE users SET id = 1;
+
+-- +goose Down
+SELECT 'down SQL query';
+`)
+		require.NoError(t, GenDown(dir, 1))
+		require.Equal(t, `-- +goose Up
+CREATE TABLE users (id int);
+CREATE INDEX users_idx ON users (id);
+UPDATE users SET id = 1;
+
+-- +goose Down
+-- TODO(goose): irreversible statement, write the down migration by hand: UPDATE users SET id = 1
+DROP INDEX users_idx;
+DROP TABLE users;
+`, readFile(t, path))
+	})
+	t.Run("placeholder_in_statement_block", func(t *testing.T) {
+		out, err := genDown([]byte(`-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE users (id int);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+`))
+		require.NoError(t, err)
+		require.Equal(t, `-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE users (id int);
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE users;
+`, string(out))
+	})
+	t.Run("missing_down_annotation", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "00001_users.sql")
+		writeFile(t, path, "-- +goose Up\nALTER TABLE users ADD COLUMN name text;\n\n")
+		require.NoError(t, GenDown(dir, 1))
+		require.Equal(t, `-- +goose Up
+ALTER TABLE users ADD COLUMN name text;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN name;
+`, readFile(t, path))
+	})
+	t.Run("down_not_empty", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "00001_users.sql")
+		writeFile(t, path, "-- +goose Up\nCREATE TABLE users (id int);\n-- +goose Down\nDROP TABLE users;\n")
+		err := GenDown(dir, 1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "down section is not empty")
+	})
+	t.Run("unknown_version", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "00001_users.sql"), "-- +goose Up\nCREATE TABLE users (id int);\n")
+		err := GenDown(dir, 2)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to find migration 2")
+	})
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
@@ -118,6 +118,17 @@ func run(ctx context.Context, command string, db *sql.DB, dir string, args []str
 		if err := Fix(dir); err != nil {
 			return err
 		}
+	case "gen-down":
+		if len(args) == 0 {
+			return fmt.Errorf("gen-down must be of form: goose [OPTIONS] gen-down VERSION")
+		}
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("version must be a number (got '%s')", args[0])
+		}
+		if err := GenDown(dir, version); err != nil {
+			return err
+		}
 	case "redo":
 		if err := RedoContext(ctx, db, dir, options...); err != nil {
 			return err
@@ -0,0 +1,165 @@
+// Package autodown generates down statements from the up statements of a SQL migration.
+//
+// Only DDL with an unambiguous inverse is handled: CREATE TABLE, CREATE INDEX, CREATE VIEW, ALTER
+// TABLE ... ADD COLUMN / ADD CONSTRAINT and renames. Anything else produces a TODO comment that must
+// be replaced by hand.
+package autodown
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// TODOPrefix starts the comment emitted for statements that cannot be reversed automatically.
+const TODOPrefix = "-- TODO(goose):"
+
+var (
+	reCreateTable = regexp.MustCompile(`(?is)^CREATE\s+(?:(?:GLOBAL\s+|LOCAL\s+)?(?:TEMP|TEMPORARY|UNLOGGED)\s+)?TABLE\s+(IF\s+NOT\s+EXISTS\s+)?([^\s(;]+)`)
+	reCreateIndex = regexp.MustCompile(`(?is)^CREATE\s+(?:UNIQUE\s+)?INDEX\s+(CONCURRENTLY\s+)?(IF\s+NOT\s+EXISTS\s+)?([^\s(;]+)\s+ON\s+(?:ONLY\s+)?([^\s(;]+)`)
+	reCreateView  = regexp.MustCompile(`(?is)^CREATE\s+(OR\s+REPLACE\s+)?(?:TEMP\s+|TEMPORARY\s+)?(MATERIALIZED\s+)?VIEW\s+(IF\s+NOT\s+EXISTS\s+)?([^\s(;]+)`)
+	reAlterTable  = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:ONLY\s+)?([^\s(;]+)\s+(.*)$`)
+	reRenameTable = regexp.MustCompile(`(?is)^RENAME\s+TABLE\s+([^\s;]+)\s+TO\s+([^\s;]+)$`)
+	reAlterIndex  = regexp.MustCompile(`(?is)^ALTER\s+INDEX\s+(IF\s+EXISTS\s+)?([^\s;]+)\s+RENAME\s+TO\s+([^\s;]+)$`)
+
+	reRenameTo      = regexp.MustCompile(`(?is)^RENAME\s+TO\s+([^\s;]+)$`)
+	reRenameColumn  = regexp.MustCompile(`(?is)^RENAME\s+(?:COLUMN\s+)?([^\s;]+)\s+TO\s+([^\s;]+)$`)
+	reAddConstraint = regexp.MustCompile(`(?is)^ADD\s+CONSTRAINT\s+([^\s(;]+)\s`)
+	reAddColumn     = regexp.MustCompile(`(?is)^ADD\s+(?:COLUMN\s+)?(IF\s+NOT\s+EXISTS\s+)?([^\s(;]+)\s`)
+	reNotColumn     = regexp.MustCompile(`(?i)^(?:CONSTRAINT|PRIMARY|UNIQUE|INDEX|KEY|FOREIGN|CHECK|FULLTEXT|SPATIAL|PARTITION)$`)
+)
+
+// Generate returns the down statements for the given up statements, in reverse order. Statements
+// that cannot be reversed produce a TODO comment, so the result always has one entry per up
+// statement.
+func Generate(up []string) []string {
+	down := make([]string, 0, len(up))
+	for _, stmt := range up {
+		down = append(down, Inverse(stmt))
+	}
+	slices.Reverse(down)
+	return down
+}
+
+// Inverse returns the statement that undoes stmt, terminated by a semicolon, or a TODO comment if
+// stmt is not recognized as reversible.
+func Inverse(stmt string) string {
+	sql := normalize(stmt)
+	if inv, ok := inverse(sql); ok {
+		return inv + ";"
+	}
+	return todo(sql)
+}
+
+func inverse(sql string) (string, bool) {
+	if m := reCreateTable.FindStringSubmatch(sql); m != nil {
+		return "DROP TABLE " + ifExists(m[1]) + m[2], true
+	}
+	if m := reCreateIndex.FindStringSubmatch(sql); m != nil {
+		return "DROP INDEX " + keyword(m[1], "CONCURRENTLY ") + ifExists(m[2]) + m[3], true
+	}
+	if m := reCreateView.FindStringSubmatch(sql); m != nil {
+		if m[1] != "" {
+			// The previous definition of the view is unknown.
+			return "", false
+		}
+		return "DROP " + keyword(m[2], "MATERIALIZED ") + "VIEW " + ifExists(m[3]) + m[4], true
+	}
+	if m := reRenameTable.FindStringSubmatch(sql); m != nil {
+		return fmt.Sprintf("RENAME TABLE %s TO %s", m[2], m[1]), true
+	}
+	if m := reAlterIndex.FindStringSubmatch(sql); m != nil {
+		return fmt.Sprintf("ALTER INDEX %s%s RENAME TO %s", keyword(m[1], "IF EXISTS "), m[3], m[2]), true
+	}
+	if m := reAlterTable.FindStringSubmatch(sql); m != nil {
+		return inverseAlterTable(m[1], m[2])
+	}
+	return "", false
+}
+
+// inverseAlterTable reverses an ALTER TABLE whose actions are all reversible.
+func inverseAlterTable(table, actions string) (string, bool) {
+	if m := reRenameTo.FindStringSubmatch(actions); m != nil {
+		return fmt.Sprintf("ALTER TABLE %s RENAME TO %s", m[1], table), true
+	}
+	if m := reRenameColumn.FindStringSubmatch(actions); m != nil {
+		return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", table, m[2], m[1]), true
+	}
+	var inverses []string
+	for _, action := range splitTopLevel(actions) {
+		if m := reAddConstraint.FindStringSubmatch(action + " "); m != nil {
+			inverses = append(inverses, "DROP CONSTRAINT "+m[1])
+			continue
+		}
+		m := reAddColumn.FindStringSubmatch(action + " ")
+		if m == nil || reNotColumn.MatchString(m[2]) {
+			return "", false
+		}
+		inverses = append(inverses, "DROP COLUMN "+ifExists(m[1])+m[2])
+	}
+	if len(inverses) == 0 {
+		return "", false
+	}
+	slices.Reverse(inverses)
+	return fmt.Sprintf("ALTER TABLE %s %s", table, strings.Join(inverses, ", ")), true
+}
+
+func todo(sql string) string {
+	first, _, _ := strings.Cut(sql, "\n")
+	const maxLen = 80
+	if len(first) > maxLen {
+		first = first[:maxLen] + "..."
+	} else if strings.Contains(sql, "\n") {
+		first += " ..."
+	}
+	return fmt.Sprintf("%s irreversible statement, write the down migration by hand: %s", TODOPrefix, first)
+}
+
+// ifExists turns a captured "IF NOT EXISTS" into "IF EXISTS ".
+func ifExists(s string) string {
+	return keyword(s, "IF EXISTS ")
+}
+
+// keyword returns kw if the optional keyword was captured, normalizing its case and spacing.
+func keyword(captured, kw string) string {
+	if captured == "" {
+		return ""
+	}
+	return kw
+}
+
+// normalize strips comments, surrounding whitespace and the trailing semicolon from a statement and
+// collapses whitespace runs, except newlines, into a single space.
+func normalize(stmt string) string {
+	var lines []string
+	for _, line := range strings.Split(stmt, "\n") {
+		if i := strings.Index(line, "--"); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.TrimRight(strings.Join(lines, "\n"), "; \n"))
+}
+
+// splitTopLevel splits s on commas that are not nested in parentheses and trims each part.
+func splitTopLevel(s string) []string {
+	var parts []string
+	var depth, start int
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
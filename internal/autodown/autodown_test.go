@@ -0,0 +1,72 @@
+package autodown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInverse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		up   string
+		want string
+	}{
+		{"CREATE TABLE users (id int);", "DROP TABLE users;"},
+		{"create table if not exists public.users (\n  id int\n);\n", "DROP TABLE IF EXISTS public.users;"},
+		{`CREATE TEMPORARY TABLE "tmp"(id int);`, `DROP TABLE "tmp";`},
+		{"CREATE INDEX users_idx ON users (id);", "DROP INDEX users_idx;"},
+		{"CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS users_idx ON users (id);", "DROP INDEX CONCURRENTLY IF EXISTS users_idx;"},
+		{"CREATE VIEW active AS SELECT * FROM users;", "DROP VIEW active;"},
+		{"CREATE MATERIALIZED VIEW stats AS SELECT 1;", "DROP MATERIALIZED VIEW stats;"},
+		{"ALTER TABLE users ADD COLUMN name text NOT NULL DEFAULT '';", "ALTER TABLE users DROP COLUMN name;"},
+		{"ALTER TABLE users ADD IF NOT EXISTS name text;", "ALTER TABLE users DROP COLUMN IF EXISTS name;"},
+		{
+			"ALTER TABLE users ADD COLUMN a numeric(10, 2), ADD COLUMN b int, ADD CONSTRAINT b_pos CHECK (b > 0);",
+			"ALTER TABLE users DROP CONSTRAINT b_pos, DROP COLUMN b, DROP COLUMN a;",
+		},
+		{"ALTER TABLE users RENAME TO accounts;", "ALTER TABLE accounts RENAME TO users;"},
+		{"ALTER TABLE users RENAME COLUMN name TO full_name;", "ALTER TABLE users RENAME COLUMN full_name TO name;"},
+		{"ALTER TABLE users RENAME name TO full_name;", "ALTER TABLE users RENAME COLUMN full_name TO name;"},
+		{"RENAME TABLE users TO accounts;", "RENAME TABLE accounts TO users;"},
+		{"ALTER INDEX users_idx RENAME TO accounts_idx;", "ALTER INDEX accounts_idx RENAME TO users_idx;"},
+		{"-- add a table\nCREATE TABLE t (id int); -- trailing", "DROP TABLE t;"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, Inverse(tt.up), tt.up)
+	}
+}
+
+func TestInverseIrreversible(t *testing.T) {
+	t.Parallel()
+
+	for _, up := range []string{
+		"INSERT INTO users VALUES (1);",
+		"DROP TABLE users;",
+		"ALTER TABLE users ALTER COLUMN id TYPE bigint;",
+		"ALTER TABLE users ADD COLUMN a int, DROP COLUMN b;",
+		"ALTER TABLE users ADD PRIMARY KEY (id);",
+		"CREATE OR REPLACE VIEW v AS SELECT 1;",
+		"CREATE FUNCTION f() RETURNS int AS $$\nBEGIN\n  RETURN 1;\nEND;\n$$ LANGUAGE plpgsql;",
+	} {
+		got := Inverse(up)
+		require.True(t, strings.HasPrefix(got, TODOPrefix), "%q: got %q", up, got)
+		require.NotContains(t, got, "\n")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	got := Generate([]string{
+		"CREATE TABLE users (id int);",
+		"CREATE INDEX users_idx ON users (id);",
+		"INSERT INTO users VALUES (1);",
+	})
+	require.Len(t, got, 3)
+	require.True(t, strings.HasPrefix(got[0], TODOPrefix))
+	require.Equal(t, "DROP INDEX users_idx;", got[1])
+	require.Equal(t, "DROP TABLE users;", got[2])
+}
@@ -0,0 +1,38 @@
+// Package cockroachdb contains helpers shared by the CockroachDB store and locker.
+package cockroachdb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sethvargo/go-retry"
+)
+
+// RetrySerializationFailureCode is the SQLSTATE of the error CockroachDB returns when it aborts a
+// transaction that conflicts with another one under serializable isolation. The client is expected
+// to retry the transaction.
+const RetrySerializationFailureCode = "40001"
+
+// IsRetryable reports whether err is a transaction restart error. It recognizes the errors of
+// drivers that expose the SQLSTATE with a SQLState method, such as pgx and lib/pq.
+func IsRetryable(err error) bool {
+	var sqlErr interface{ SQLState() string }
+	return errors.As(err, &sqlErr) && sqlErr.SQLState() == RetrySerializationFailureCode
+}
+
+// Retry calls fn until it succeeds, returns an error that is not a transaction restart error, or
+// the retries are exhausted. fn must run its own implicit or explicit transaction: a statement of a
+// transaction that was aborted cannot be retried on its own.
+func Retry(ctx context.Context, fn func(context.Context) error) error {
+	b := retry.WithMaxRetries(5, retry.WithJitterPercent(25, retry.NewExponential(50*time.Millisecond)))
+	return retry.Do(ctx, b, func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			if IsRetryable(err) {
+				return retry.RetryableError(err)
+			}
+			return err
+		}
+		return nil
+	})
+}
@@ -12,7 +12,13 @@ import (
 // that are not part of the core Store interface.
 type StoreController struct{ database.Store }
 
-var _ database.StoreExtender = (*StoreController)(nil)
+var (
+	_ database.StoreExtender       = (*StoreController)(nil)
+	_ database.HistoryLister       = (*StoreController)(nil)
+	_ database.FutureHistoryLister = (*StoreController)(nil)
+	_ database.TypeLister          = (*StoreController)(nil)
+	_ database.TableUpgrader       = (*StoreController)(nil)
+)
 
 // NewStoreController returns a new StoreController that wraps the given Store.
 //
@@ -20,6 +26,10 @@ var _ database.StoreExtender = (*StoreController)(nil)
 // appropriate:
 //
 //   - TableExists(context.Context, DBTxConn) (bool, error)
+//   - ListHistory(context.Context, DBTxConn) ([]*database.HistoryResult, error)
+//   - ListFutureHistory(context.Context, DBTxConn) ([]*database.HistoryResult, error)
+//   - ListTypes(context.Context, DBTxConn) (map[int64]string, error)
+//   - UpgradeTable(context.Context, DBTxConn) (*database.TableUpgradeResult, error)
 //
 // If the Store does not implement a method, it will either return a [errors.ErrUnsupported] error
 // or fall back to the default behavior.
@@ -35,3 +45,31 @@ func (c *StoreController) TableExists(ctx context.Context, db database.DBTxConn)
 	}
 	return false, errors.ErrUnsupported
 }
+
+func (c *StoreController) ListHistory(ctx context.Context, db database.DBTxConn) ([]*database.HistoryResult, error) {
+	if t, ok := c.Store.(database.HistoryLister); ok {
+		return t.ListHistory(ctx, db)
+	}
+	return nil, errors.ErrUnsupported
+}
+
+func (c *StoreController) ListFutureHistory(ctx context.Context, db database.DBTxConn) ([]*database.HistoryResult, error) {
+	if t, ok := c.Store.(database.FutureHistoryLister); ok {
+		return t.ListFutureHistory(ctx, db)
+	}
+	return nil, errors.ErrUnsupported
+}
+
+func (c *StoreController) ListTypes(ctx context.Context, db database.DBTxConn) (map[int64]string, error) {
+	if t, ok := c.Store.(database.TypeLister); ok {
+		return t.ListTypes(ctx, db)
+	}
+	return nil, errors.ErrUnsupported
+}
+
+func (c *StoreController) UpgradeTable(ctx context.Context, db database.DBTxConn) (*database.TableUpgradeResult, error) {
+	if t, ok := c.Store.(database.TableUpgrader); ok {
+		return t.UpgradeTable(ctx, db)
+	}
+	return nil, errors.ErrUnsupported
+}
@@ -1,7 +1,9 @@
 package dialects
 
 import (
+	"cmp"
 	"fmt"
+	"strings"
 
 	"github.com/pressly/goose/v3/database/dialect"
 )
@@ -11,11 +13,59 @@ func NewClickhouse() dialect.Querier {
 	return &clickhouse{}
 }
 
-type clickhouse struct{}
+// ClickhouseOptions configures the version table of the Clickhouse dialect.
+type ClickhouseOptions struct {
+	// Cluster creates the version table ON CLUSTER with a ReplicatedMergeTree engine.
+	Cluster string
+	// ZooKeeperPath and ReplicaName are the ReplicatedMergeTree engine arguments.
+	ZooKeeperPath string
+	ReplicaName   string
+	// Final reads the version table with FINAL.
+	Final bool
+	// SequentialConsistency writes versions with an insert quorum and reads them with
+	// select_sequential_consistency.
+	SequentialConsistency bool
+}
+
+const (
+	// The default path has no {shard} macro: every node of the cluster is a replica of the same
+	// table, so each one holds the full migration history.
+	defaultClickhouseZooKeeperPath = "/clickhouse/tables/{database}/{table}"
+	defaultClickhouseReplicaName   = "{replica}"
+)
+
+// NewClickhouseWithOptions returns a new [dialect.Querier] for Clickhouse dialect configured with
+// opts. The zero options are the same as [NewClickhouse].
+func NewClickhouseWithOptions(opts ClickhouseOptions) dialect.Querier {
+	return &clickhouse{opts: opts}
+}
+
+type clickhouse struct {
+	opts ClickhouseOptions
+}
 
-var _ dialect.Querier = (*clickhouse)(nil)
+var (
+	_ dialect.Querier             = (*clickhouse)(nil)
+	_ dialect.HistoryLister       = (*clickhouse)(nil)
+	_ dialect.FutureHistoryLister = (*clickhouse)(nil)
+)
 
 func (c *clickhouse) CreateTable(tableName string) string {
+	if c.opts.Cluster != "" {
+		q := `CREATE TABLE IF NOT EXISTS %s ON CLUSTER %s (
+		version_id Int64,
+		is_applied UInt8,
+		date Date default now(),
+		tstamp DateTime default now()
+	  )
+	  ENGINE = ReplicatedMergeTree(%s, %s)
+		ORDER BY (date)`
+		return fmt.Sprintf(q, tableName,
+			clickhouseQuote(c.opts.Cluster),
+			clickhouseQuote(cmp.Or(c.opts.ZooKeeperPath, defaultClickhouseZooKeeperPath)),
+			clickhouseQuote(cmp.Or(c.opts.ReplicaName, defaultClickhouseReplicaName)),
+		)
+	}
 	q := `CREATE TABLE IF NOT EXISTS %s (
 		version_id Int64,
 		is_applied UInt8,
@@ -28,26 +78,66 @@ func (c *clickhouse) CreateTable(tableName string) string {
 }
 
 func (c *clickhouse) InsertVersion(tableName string) string {
+	if c.opts.SequentialConsistency {
+		// select_sequential_consistency only sees inserts written with a quorum, and does not work
+		// with parallel quorum inserts.
+		q := `INSERT INTO %s (version_id, is_applied) SETTINGS insert_quorum = 'auto', insert_quorum_parallel = 0 VALUES ($1, $2)`
+		return fmt.Sprintf(q, tableName)
+	}
 	q := `INSERT INTO %s (version_id, is_applied) VALUES ($1, $2)`
 	return fmt.Sprintf(q, tableName)
 }
 
 func (c *clickhouse) DeleteVersion(tableName string) string {
+	// A replicated table applies the mutation on every replica, mutations_sync = 2 waits for all
+	// of them.
 	q := `ALTER TABLE %s DELETE WHERE version_id = $1 SETTINGS mutations_sync = 2`
 	return fmt.Sprintf(q, tableName)
 }
 
 func (c *clickhouse) GetMigrationByVersion(tableName string) string {
-	q := `SELECT tstamp, is_applied FROM %s WHERE version_id = $1 ORDER BY tstamp DESC LIMIT 1`
-	return fmt.Sprintf(q, tableName)
+	q := `SELECT tstamp, is_applied FROM %s WHERE version_id = $1 ORDER BY tstamp DESC LIMIT 1%s`
+	return fmt.Sprintf(q, c.from(tableName), c.settings())
 }
 
 func (c *clickhouse) ListMigrations(tableName string) string {
-	q := `SELECT version_id, is_applied FROM %s ORDER BY version_id DESC`
-	return fmt.Sprintf(q, tableName)
+	q := `SELECT version_id, is_applied FROM %s ORDER BY version_id DESC%s`
+	return fmt.Sprintf(q, c.from(tableName), c.settings())
+}
+
+func (c *clickhouse) ListHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s ORDER BY tstamp ASC%s`
+	return fmt.Sprintf(q, c.from(tableName), c.settings())
+}
+
+func (c *clickhouse) ListFutureHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s WHERE tstamp > now() ORDER BY tstamp ASC%s`
+	return fmt.Sprintf(q, c.from(tableName), c.settings())
 }
 
 func (c *clickhouse) GetLatestVersion(tableName string) string {
-	q := `SELECT max(version_id) FROM %s`
-	return fmt.Sprintf(q, tableName)
+	q := `SELECT max(version_id) FROM %s%s`
+	return fmt.Sprintf(q, c.from(tableName), c.settings())
+}
+
+// from returns the table to read versions from, with the FINAL modifier if configured.
+func (c *clickhouse) from(tableName string) string {
+	if c.opts.Final {
+		return tableName + " FINAL"
+	}
+	return tableName
+}
+
+// settings returns the SETTINGS clause of the queries that read versions.
+func (c *clickhouse) settings() string {
+	if c.opts.SequentialConsistency {
+		return " SETTINGS select_sequential_consistency = 1"
+	}
+	return ""
+}
+
+// clickhouseQuote returns s as a ClickHouse string literal. Macros such as {cluster} are expanded
+// by the server inside literals.
+func clickhouseQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
 }
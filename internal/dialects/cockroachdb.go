@@ -0,0 +1,83 @@
+package dialects
+
+import (
+	"fmt"
+
+	"github.com/pressly/goose/v3/database/dialect"
+)
+
+// NewCockroachDB returns a new [dialect.Querier] for CockroachDB dialect.
+func NewCockroachDB() dialect.QuerierExtender {
+	return &cockroachDB{}
+}
+
+type cockroachDB struct{}
+
+var (
+	_ dialect.QuerierExtender     = (*cockroachDB)(nil)
+	_ dialect.SchemaCreator       = (*cockroachDB)(nil)
+	_ dialect.HistoryLister       = (*cockroachDB)(nil)
+	_ dialect.FutureHistoryLister = (*cockroachDB)(nil)
+)
+
+func (c *cockroachDB) CreateTable(tableName string) string {
+	// Sequential ids, such as identity columns, send every insert to the same range. unique_rowid()
+	// spreads them out and still increases over time, which ListMigrations relies on.
+	q := `CREATE TABLE %s (
+		id INT8 NOT NULL DEFAULT unique_rowid() PRIMARY KEY,
+		version_id INT8 NOT NULL,
+		is_applied BOOL NOT NULL,
+		tstamp TIMESTAMP NOT NULL DEFAULT now()
+	)`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (c *cockroachDB) InsertVersion(tableName string) string {
+	q := `INSERT INTO %s (version_id, is_applied) VALUES ($1, $2)`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (c *cockroachDB) DeleteVersion(tableName string) string {
+	q := `DELETE FROM %s WHERE version_id=$1`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (c *cockroachDB) GetMigrationByVersion(tableName string) string {
+	q := `SELECT tstamp, is_applied FROM %s WHERE version_id=$1 ORDER BY tstamp DESC LIMIT 1`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (c *cockroachDB) ListMigrations(tableName string) string {
+	q := `SELECT version_id, is_applied FROM %s ORDER BY id DESC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (c *cockroachDB) ListHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (c *cockroachDB) ListFutureHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s WHERE tstamp > LOCALTIMESTAMP ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (c *cockroachDB) GetLatestVersion(tableName string) string {
+	q := `SELECT max(version_id) FROM %s`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (c *cockroachDB) TableExists(tableName string) string {
+	// information_schema.tables only lists the tables of the current database.
+	schemaName, tableName := parseTableIdentifier(tableName)
+	if schemaName != "" {
+		q := `SELECT EXISTS ( SELECT 1 FROM information_schema.tables WHERE table_schema = '%s' AND table_name = '%s' )`
+		return fmt.Sprintf(q, schemaName, tableName)
+	}
+	q := `SELECT EXISTS ( SELECT 1 FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = '%s' )`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (c *cockroachDB) CreateSchema(tableName, owner string) string {
+	return CreateSchema(tableName, owner)
+}
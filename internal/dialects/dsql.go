@@ -13,7 +13,12 @@ func NewAuroraDSQL() dialect.QuerierExtender {
 
 type dsql struct{}
 
-var _ dialect.QuerierExtender = (*dsql)(nil)
+var (
+	_ dialect.QuerierExtender     = (*dsql)(nil)
+	_ dialect.SchemaCreator       = (*dsql)(nil)
+	_ dialect.HistoryLister       = (*dsql)(nil)
+	_ dialect.FutureHistoryLister = (*dsql)(nil)
+)
 
 func (d *dsql) CreateTable(tableName string) string {
 	q := `CREATE TABLE %s (
@@ -50,6 +55,16 @@ func (d *dsql) ListMigrations(tableName string) string {
 	return fmt.Sprintf(q, tableName)
 }
 
+func (d *dsql) ListHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (d *dsql) ListFutureHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s WHERE tstamp > LOCALTIMESTAMP ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
 func (d *dsql) GetLatestVersion(tableName string) string {
 	q := `SELECT max(version_id) FROM %s`
 	return fmt.Sprintf(q, tableName)
@@ -64,3 +79,7 @@ func (d *dsql) TableExists(tableName string) string {
 	q := `SELECT EXISTS ( SELECT 1 FROM pg_tables WHERE (current_schema() IS NULL OR schemaname = current_schema()) AND tablename = '%s' )`
 	return fmt.Sprintf(q, tableName)
 }
+
+func (d *dsql) CreateSchema(tableName, owner string) string {
+	return CreateSchema(tableName, owner)
+}
@@ -0,0 +1,78 @@
+package dialects
+
+import (
+	"fmt"
+
+	"github.com/pressly/goose/v3/database/dialect"
+)
+
+// NewDuckDB returns a new [dialect.Querier] for DuckDB dialect.
+func NewDuckDB() dialect.QuerierExtender {
+	return &duckdb{}
+}
+
+type duckdb struct{}
+
+var (
+	_ dialect.QuerierExtender     = (*duckdb)(nil)
+	_ dialect.HistoryLister       = (*duckdb)(nil)
+	_ dialect.FutureHistoryLister = (*duckdb)(nil)
+)
+
+func (d *duckdb) CreateTable(tableName string) string {
+	// DuckDB supports neither AUTOINCREMENT nor GENERATED identity columns, ids come from a
+	// sequence named after the table.
+	q := `CREATE SEQUENCE IF NOT EXISTS %[1]s_id_seq START 1;
+	CREATE TABLE %[1]s (
+		id INTEGER PRIMARY KEY DEFAULT nextval('%[1]s_id_seq'),
+		version_id BIGINT NOT NULL,
+		is_applied BOOLEAN NOT NULL,
+		tstamp TIMESTAMP NOT NULL DEFAULT current_timestamp
+	)`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (d *duckdb) InsertVersion(tableName string) string {
+	q := `INSERT INTO %s (version_id, is_applied) VALUES ($1, $2)`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (d *duckdb) DeleteVersion(tableName string) string {
+	q := `DELETE FROM %s WHERE version_id=$1`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (d *duckdb) GetMigrationByVersion(tableName string) string {
+	q := `SELECT tstamp, is_applied FROM %s WHERE version_id=$1 ORDER BY tstamp DESC LIMIT 1`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (d *duckdb) ListMigrations(tableName string) string {
+	q := `SELECT version_id, is_applied from %s ORDER BY id DESC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (d *duckdb) ListHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (d *duckdb) ListFutureHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s WHERE tstamp > CAST(current_timestamp AS TIMESTAMP) ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (d *duckdb) GetLatestVersion(tableName string) string {
+	q := `SELECT max(version_id) FROM %s`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (d *duckdb) TableExists(tableName string) string {
+	schemaName, tableName := parseTableIdentifier(tableName)
+	if schemaName != "" {
+		q := `SELECT EXISTS ( SELECT 1 FROM information_schema.tables WHERE table_schema = '%s' AND table_name = '%s' )`
+		return fmt.Sprintf(q, schemaName, tableName)
+	}
+	q := `SELECT EXISTS ( SELECT 1 FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = '%s' )`
+	return fmt.Sprintf(q, tableName)
+}
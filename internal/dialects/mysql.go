@@ -13,7 +13,11 @@ func NewMysql() dialect.QuerierExtender {
 
 type mysql struct{}
 
-var _ dialect.QuerierExtender = (*mysql)(nil)
+var (
+	_ dialect.QuerierExtender     = (*mysql)(nil)
+	_ dialect.HistoryLister       = (*mysql)(nil)
+	_ dialect.FutureHistoryLister = (*mysql)(nil)
+)
 
 func (m *mysql) CreateTable(tableName string) string {
 	q := `CREATE TABLE %s (
@@ -46,6 +50,16 @@ func (m *mysql) ListMigrations(tableName string) string {
 	return fmt.Sprintf(q, tableName)
 }
 
+func (m *mysql) ListHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (m *mysql) ListFutureHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s WHERE tstamp > now() ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
 func (m *mysql) GetLatestVersion(tableName string) string {
 	q := `SELECT MAX(version_id) FROM %s`
 	return fmt.Sprintf(q, tableName)
@@ -0,0 +1,77 @@
+package dialects
+
+import (
+	"fmt"
+
+	"github.com/pressly/goose/v3/database/dialect"
+)
+
+// NewOracle returns a new [dialect.Querier] for Oracle dialect.
+func NewOracle() dialect.QuerierExtender {
+	return &oracle{}
+}
+
+type oracle struct{}
+
+var (
+	_ dialect.QuerierExtender     = (*oracle)(nil)
+	_ dialect.HistoryLister       = (*oracle)(nil)
+	_ dialect.FutureHistoryLister = (*oracle)(nil)
+)
+
+func (o *oracle) CreateTable(tableName string) string {
+	// Oracle has no boolean column type before 23ai, is_applied is stored as 0 or 1.
+	q := `CREATE TABLE %s (
+		id NUMBER(19) GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+		version_id NUMBER(19) NOT NULL,
+		is_applied NUMBER(1) NOT NULL,
+		tstamp TIMESTAMP DEFAULT SYSTIMESTAMP NOT NULL
+	)`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (o *oracle) InsertVersion(tableName string) string {
+	q := `INSERT INTO %s (version_id, is_applied) VALUES (:1, :2)`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (o *oracle) DeleteVersion(tableName string) string {
+	q := `DELETE FROM %s WHERE version_id=:1`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (o *oracle) GetMigrationByVersion(tableName string) string {
+	q := `SELECT tstamp, is_applied FROM %s WHERE version_id=:1 ORDER BY tstamp DESC FETCH FIRST 1 ROWS ONLY`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (o *oracle) ListMigrations(tableName string) string {
+	q := `SELECT version_id, is_applied FROM %s ORDER BY id DESC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (o *oracle) ListHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (o *oracle) ListFutureHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s WHERE tstamp > CAST(SYSTIMESTAMP AS TIMESTAMP) ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (o *oracle) GetLatestVersion(tableName string) string {
+	q := `SELECT MAX(version_id) FROM %s`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (o *oracle) TableExists(tableName string) string {
+	// Unquoted identifiers are stored in upper case in the data dictionary.
+	schemaName, tableName := parseTableIdentifier(tableName)
+	if schemaName != "" {
+		q := `SELECT COUNT(*) FROM all_tables WHERE owner = UPPER('%s') AND table_name = UPPER('%s')`
+		return fmt.Sprintf(q, schemaName, tableName)
+	}
+	q := `SELECT COUNT(*) FROM user_tables WHERE table_name = UPPER('%s')`
+	return fmt.Sprintf(q, tableName)
+}
@@ -14,7 +14,14 @@ func NewPostgres() dialect.QuerierExtender {
 
 type postgres struct{}
 
-var _ dialect.QuerierExtender = (*postgres)(nil)
+var (
+	_ dialect.QuerierExtender     = (*postgres)(nil)
+	_ dialect.TableUpgrader       = (*postgres)(nil)
+	_ dialect.SchemaCreator       = (*postgres)(nil)
+	_ dialect.TypeRecorder        = (*postgres)(nil)
+	_ dialect.HistoryLister       = (*postgres)(nil)
+	_ dialect.FutureHistoryLister = (*postgres)(nil)
+)
 
 func (p *postgres) CreateTable(tableName string) string {
 	q := `CREATE TABLE %s (
@@ -46,6 +53,16 @@ func (p *postgres) ListMigrations(tableName string) string {
 	return fmt.Sprintf(q, tableName)
 }
 
+func (p *postgres) ListHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (p *postgres) ListFutureHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s WHERE tstamp > LOCALTIMESTAMP ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
 func (p *postgres) GetLatestVersion(tableName string) string {
 	q := `SELECT max(version_id) FROM %s`
 	return fmt.Sprintf(q, tableName)
@@ -61,6 +78,65 @@ func (p *postgres) TableExists(tableName string) string {
 	return fmt.Sprintf(q, tableName)
 }
 
+func (p *postgres) TableUpgrades(tableName string) []dialect.TableUpgrade {
+	schemaName, name := parseTableIdentifier(tableName)
+	// The index is created in the schema of the table, so its name must not be qualified.
+	index := name + "_version_id_idx"
+	check := fmt.Sprintf(`SELECT EXISTS ( SELECT 1 FROM pg_indexes WHERE schemaname = current_schema() AND indexname = '%s' )`, index)
+	if schemaName != "" {
+		check = fmt.Sprintf(`SELECT EXISTS ( SELECT 1 FROM pg_indexes WHERE schemaname = '%s' AND indexname = '%s' )`, schemaName, index)
+	}
+	return []dialect.TableUpgrade{
+		// Layout 2: index version_id, which GetMigrationByVersion and DeleteVersion filter on.
+		{
+			Check:      check,
+			Statements: []string{fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (version_id)`, index, tableName)},
+		},
+		// Layout 3: record the migration type, see dialect.TypeRecorder.
+		{
+			Check:      p.TypeColumnExists(tableName),
+			Statements: []string{fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS migration_type text`, tableName)},
+		},
+	}
+}
+
+func (p *postgres) TypeColumnExists(tableName string) string {
+	schemaName, name := parseTableIdentifier(tableName)
+	if schemaName != "" {
+		q := `SELECT EXISTS ( SELECT 1 FROM information_schema.columns WHERE table_schema = '%s' AND table_name = '%s' AND column_name = 'migration_type' )`
+		return fmt.Sprintf(q, schemaName, name)
+	}
+	q := `SELECT EXISTS ( SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = '%s' AND column_name = 'migration_type' )`
+	return fmt.Sprintf(q, name)
+}
+
+func (p *postgres) InsertVersionType(tableName string) string {
+	q := `INSERT INTO %s (version_id, is_applied, migration_type) VALUES ($1, $2, $3)`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (p *postgres) ListTypes(tableName string) string {
+	q := `SELECT version_id, migration_type FROM %s WHERE migration_type IS NOT NULL ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (p *postgres) CreateSchema(tableName, owner string) string {
+	return CreateSchema(tableName, owner)
+}
+
+// CreateSchema returns the CREATE SCHEMA statement shared by the Postgres family of dialects and
+// the Postgres lock table, or an empty string if tableName is not schema-qualified.
+func CreateSchema(tableName, owner string) string {
+	schemaName, _ := parseTableIdentifier(tableName)
+	if schemaName == "" {
+		return ""
+	}
+	if owner != "" {
+		return fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS %s AUTHORIZATION %s`, schemaName, owner)
+	}
+	return fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS %s`, schemaName)
+}
+
 func parseTableIdentifier(name string) (schema, table string) {
 	schema, table, found := strings.Cut(name, ".")
 	if !found {
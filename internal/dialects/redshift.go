@@ -13,7 +13,12 @@ func NewRedshift() dialect.Querier {
 
 type redshift struct{}
 
-var _ dialect.Querier = (*redshift)(nil)
+var (
+	_ dialect.Querier             = (*redshift)(nil)
+	_ dialect.SchemaCreator       = (*redshift)(nil)
+	_ dialect.HistoryLister       = (*redshift)(nil)
+	_ dialect.FutureHistoryLister = (*redshift)(nil)
+)
 
 func (r *redshift) CreateTable(tableName string) string {
 	q := `CREATE TABLE %s (
@@ -46,7 +51,21 @@ func (r *redshift) ListMigrations(tableName string) string {
 	return fmt.Sprintf(q, tableName)
 }
 
+func (r *redshift) ListHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (r *redshift) ListFutureHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s WHERE tstamp > sysdate ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
 func (r *redshift) GetLatestVersion(tableName string) string {
 	q := `SELECT max(version_id) FROM %s`
 	return fmt.Sprintf(q, tableName)
 }
+
+func (r *redshift) CreateSchema(tableName, owner string) string {
+	return CreateSchema(tableName, owner)
+}
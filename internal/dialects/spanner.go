@@ -13,7 +13,11 @@ func NewSpanner() dialect.Querier {
 
 type spanner struct{}
 
-var _ dialect.Querier = (*spanner)(nil)
+var (
+	_ dialect.Querier             = (*spanner)(nil)
+	_ dialect.HistoryLister       = (*spanner)(nil)
+	_ dialect.FutureHistoryLister = (*spanner)(nil)
+)
 
 func (s *spanner) CreateTable(tableName string) string {
 	q := `CREATE TABLE %s (
@@ -44,6 +48,16 @@ func (s *spanner) ListMigrations(tableName string) string {
 	return fmt.Sprintf(q, tableName)
 }
 
+func (s *spanner) ListHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s ORDER BY tstamp ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (s *spanner) ListFutureHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s WHERE tstamp > CURRENT_TIMESTAMP() ORDER BY tstamp ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
 func (s *spanner) GetLatestVersion(tableName string) string {
 	q := `SELECT MAX(version_id) FROM %s`
 	return fmt.Sprintf(q, tableName)
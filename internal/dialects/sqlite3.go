@@ -13,7 +13,13 @@ func NewSqlite3() dialect.Querier {
 
 type sqlite3 struct{}
 
-var _ dialect.Querier = (*sqlite3)(nil)
+var (
+	_ dialect.Querier             = (*sqlite3)(nil)
+	_ dialect.TableUpgrader       = (*sqlite3)(nil)
+	_ dialect.TypeRecorder        = (*sqlite3)(nil)
+	_ dialect.HistoryLister       = (*sqlite3)(nil)
+	_ dialect.FutureHistoryLister = (*sqlite3)(nil)
+)
 
 func (s *sqlite3) CreateTable(tableName string) string {
 	q := `CREATE TABLE %s (
@@ -45,7 +51,58 @@ func (s *sqlite3) ListMigrations(tableName string) string {
 	return fmt.Sprintf(q, tableName)
 }
 
+func (s *sqlite3) ListHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (s *sqlite3) ListFutureHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s WHERE tstamp > datetime('now') ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
 func (s *sqlite3) GetLatestVersion(tableName string) string {
 	q := `SELECT MAX(version_id) FROM %s`
 	return fmt.Sprintf(q, tableName)
 }
+
+func (s *sqlite3) TableUpgrades(tableName string) []dialect.TableUpgrade {
+	// SQLite qualifies the index, not the table it is created on, with the schema.
+	schemaName, name := parseTableIdentifier(tableName)
+	index, master := name+"_version_id_idx", "sqlite_master"
+	if schemaName != "" {
+		index, master = schemaName+"."+index, schemaName+".sqlite_master"
+	}
+	return []dialect.TableUpgrade{
+		// Layout 2: index version_id, which GetMigrationByVersion and DeleteVersion filter on.
+		{
+			Check:      fmt.Sprintf(`SELECT EXISTS ( SELECT 1 FROM %s WHERE type = 'index' AND name = '%s' )`, master, name+"_version_id_idx"),
+			Statements: []string{fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (version_id)`, index, name)},
+		},
+		// Layout 3: record the migration type, see dialect.TypeRecorder.
+		{
+			Check:      s.TypeColumnExists(tableName),
+			Statements: []string{fmt.Sprintf(`ALTER TABLE %s ADD COLUMN migration_type TEXT`, tableName)},
+		},
+	}
+}
+
+func (s *sqlite3) TypeColumnExists(tableName string) string {
+	schemaName, name := parseTableIdentifier(tableName)
+	if schemaName != "" {
+		q := `SELECT EXISTS ( SELECT 1 FROM pragma_table_info('%s', '%s') WHERE name = 'migration_type' )`
+		return fmt.Sprintf(q, name, schemaName)
+	}
+	q := `SELECT EXISTS ( SELECT 1 FROM pragma_table_info('%s') WHERE name = 'migration_type' )`
+	return fmt.Sprintf(q, name)
+}
+
+func (s *sqlite3) InsertVersionType(tableName string) string {
+	q := `INSERT INTO %s (version_id, is_applied, migration_type) VALUES (?, ?, ?)`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (s *sqlite3) ListTypes(tableName string) string {
+	q := `SELECT version_id, migration_type FROM %s WHERE migration_type IS NOT NULL ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
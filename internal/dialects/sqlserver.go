@@ -13,7 +13,11 @@ func NewSqlserver() dialect.Querier {
 
 type sqlserver struct{}
 
-var _ dialect.Querier = (*sqlserver)(nil)
+var (
+	_ dialect.Querier             = (*sqlserver)(nil)
+	_ dialect.HistoryLister       = (*sqlserver)(nil)
+	_ dialect.FutureHistoryLister = (*sqlserver)(nil)
+)
 
 func (s *sqlserver) CreateTable(tableName string) string {
 	q := `CREATE TABLE %s (
@@ -45,6 +49,16 @@ func (s *sqlserver) ListMigrations(tableName string) string {
 	return fmt.Sprintf(q, tableName)
 }
 
+func (s *sqlserver) ListHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (s *sqlserver) ListFutureHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s WHERE tstamp > CURRENT_TIMESTAMP ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
 func (s *sqlserver) GetLatestVersion(tableName string) string {
 	q := `SELECT MAX(version_id) FROM %s`
 	return fmt.Sprintf(q, tableName)
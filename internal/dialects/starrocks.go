@@ -13,7 +13,11 @@ func NewStarrocks() dialect.Querier {
 
 type starrocks struct{}
 
-var _ dialect.Querier = (*starrocks)(nil)
+var (
+	_ dialect.Querier             = (*starrocks)(nil)
+	_ dialect.HistoryLister       = (*starrocks)(nil)
+	_ dialect.FutureHistoryLister = (*starrocks)(nil)
+)
 
 func (m *starrocks) CreateTable(tableName string) string {
 	q := `CREATE TABLE IF NOT EXISTS %s (
@@ -48,6 +52,16 @@ func (m *starrocks) ListMigrations(tableName string) string {
 	return fmt.Sprintf(q, tableName)
 }
 
+func (m *starrocks) ListHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (m *starrocks) ListFutureHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s WHERE tstamp > now() ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
 func (m *starrocks) GetLatestVersion(tableName string) string {
 	q := `SELECT MAX(version_id) FROM %s`
 	return fmt.Sprintf(q, tableName)
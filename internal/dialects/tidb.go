@@ -13,7 +13,11 @@ func NewTidb() dialect.Querier {
 
 type Tidb struct{}
 
-var _ dialect.Querier = (*Tidb)(nil)
+var (
+	_ dialect.Querier             = (*Tidb)(nil)
+	_ dialect.HistoryLister       = (*Tidb)(nil)
+	_ dialect.FutureHistoryLister = (*Tidb)(nil)
+)
 
 func (t *Tidb) CreateTable(tableName string) string {
 	q := `CREATE TABLE %s (
@@ -46,6 +50,16 @@ func (t *Tidb) ListMigrations(tableName string) string {
 	return fmt.Sprintf(q, tableName)
 }
 
+func (t *Tidb) ListHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (t *Tidb) ListFutureHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s WHERE tstamp > now() ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
 func (t *Tidb) GetLatestVersion(tableName string) string {
 	q := `SELECT MAX(version_id) FROM %s`
 	return fmt.Sprintf(q, tableName)
@@ -15,7 +15,11 @@ func NewVertica() dialect.Querier {
 
 type vertica struct{}
 
-var _ dialect.Querier = (*vertica)(nil)
+var (
+	_ dialect.Querier             = (*vertica)(nil)
+	_ dialect.HistoryLister       = (*vertica)(nil)
+	_ dialect.FutureHistoryLister = (*vertica)(nil)
+)
 
 func (v *vertica) CreateTable(tableName string) string {
 	q := `CREATE TABLE %s (
@@ -48,6 +52,16 @@ func (v *vertica) ListMigrations(tableName string) string {
 	return fmt.Sprintf(q, tableName)
 }
 
+func (v *vertica) ListHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
+func (v *vertica) ListFutureHistory(tableName string) string {
+	q := `SELECT version_id, is_applied, tstamp FROM %s WHERE tstamp > LOCALTIMESTAMP ORDER BY id ASC`
+	return fmt.Sprintf(q, tableName)
+}
+
 func (v *vertica) GetLatestVersion(tableName string) string {
 	q := `SELECT MAX(version_id) FROM %s`
 	return fmt.Sprintf(q, tableName)
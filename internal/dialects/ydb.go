@@ -13,7 +13,11 @@ func NewYDB() dialect.Querier {
 
 type ydb struct{}
 
-var _ dialect.Querier = (*ydb)(nil)
+var (
+	_ dialect.Querier             = (*ydb)(nil)
+	_ dialect.HistoryLister       = (*ydb)(nil)
+	_ dialect.FutureHistoryLister = (*ydb)(nil)
+)
 
 func formatYDBTableName(tableName string) string {
 	return fmt.Sprintf("`%s`", tableName)
@@ -65,6 +69,18 @@ func (c *ydb) ListMigrations(tableName string) string {
 	return fmt.Sprintf(q, formatedYDBTableName)
 }
 
+func (c *ydb) ListHistory(tableName string) string {
+	formatedYDBTableName := formatYDBTableName(tableName)
+	q := `SELECT version_id, is_applied, tstamp FROM %s ORDER BY tstamp ASC`
+	return fmt.Sprintf(q, formatedYDBTableName)
+}
+
+func (c *ydb) ListFutureHistory(tableName string) string {
+	formatedYDBTableName := formatYDBTableName(tableName)
+	q := `SELECT version_id, is_applied, tstamp FROM %s WHERE tstamp > CurrentUtcTimestamp() ORDER BY tstamp ASC`
+	return fmt.Sprintf(q, formatedYDBTableName)
+}
+
 func (c *ydb) GetLatestVersion(tableName string) string {
 	formatedYDBTableName := formatYDBTableName(tableName)
 	q := `SELECT MAX(version_id) FROM %s`
@@ -8,7 +8,6 @@ import (
 
 	"github.com/pressly/goose/v3/database"
 	"github.com/pressly/goose/v3/database/dialect"
-	"github.com/pressly/goose/v3/internal/dialects"
 )
 
 // Store is the interface that wraps the basic methods for a database dialect.
@@ -47,35 +46,10 @@ type Store interface {
 	ListMigrations(ctx context.Context, db *sql.DB, tableName string) ([]*ListMigrationsResult, error)
 }
 
-// NewStore returns a new Store for the given dialect.
+// NewStore returns a new Store for the given built-in or registered dialect.
 func NewStore(d database.Dialect) (Store, error) {
-	var querier dialect.Querier
-	switch d {
-	case database.DialectPostgres:
-		querier = dialects.NewPostgres()
-	case database.DialectMySQL:
-		querier = dialects.NewMysql()
-	case database.DialectSQLite3:
-		querier = dialects.NewSqlite3()
-	case database.DialectSpanner:
-		querier = dialects.NewSpanner()
-	case database.DialectMSSQL:
-		querier = dialects.NewSqlserver()
-	case database.DialectRedshift:
-		querier = dialects.NewRedshift()
-	case database.DialectTiDB:
-		querier = dialects.NewTidb()
-	case database.DialectClickHouse:
-		querier = dialects.NewClickhouse()
-	case database.DialectVertica:
-		querier = dialects.NewVertica()
-	case database.DialectYdB:
-		querier = dialects.NewYDB()
-	case database.DialectTurso:
-		querier = dialects.NewTurso()
-	case database.DialectStarrocks:
-		querier = dialects.NewStarrocks()
-	default:
+	querier, ok := database.LookupDialect(d)
+	if !ok {
 		return nil, fmt.Errorf("unknown querier dialect: %v", d)
 	}
 	return &store{querier: querier}, nil
@@ -1,11 +1,13 @@
 package migrationstats
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"path/filepath"
 
 	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/internal/sqlparser"
 )
 
 // FileWalker walks all files for GatherStats.
@@ -43,6 +45,11 @@ func GatherStats(fw FileWalker, debug bool) ([]*Stats, error) {
 		case ".sql":
 			m, err := parseSQLFile(r, debug)
 			if err != nil {
+				// A parse error reports the path of the file itself.
+				var parseErr *sqlparser.ParseError
+				if errors.As(err, &parseErr) {
+					return fmt.Errorf("failed to parse file: %w", sqlparser.WithPath(err, filename))
+				}
 				return fmt.Errorf("failed to parse file %q: %w", filename, err)
 			}
 			up, down = m.upCount, m.downCount
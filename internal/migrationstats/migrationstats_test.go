@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pressly/goose/v3/internal/sqlparser"
 	"github.com/stretchr/testify/require"
 )
 
@@ -92,6 +93,20 @@ func TestParsingGoMigrationsError(t *testing.T) {
 	require.Contains(t, err.Error(), "AddMigration, AddMigrationNoTx, AddMigrationContext, AddMigrationNoTxContext")
 }
 
+func TestSQLParseError(t *testing.T) {
+	t.Parallel()
+	filename := filepath.Join(t.TempDir(), "001_broken.sql")
+	err := os.WriteFile(filename, []byte("-- +goose Up\n-- +goose StatementBegin\nSELECT 1;\n"), 0644)
+	require.NoError(t, err)
+	_, err = GatherStats(NewFileWalker(filename), false)
+	require.Error(t, err)
+	var parseErr *sqlparser.ParseError
+	require.ErrorAs(t, err, &parseErr)
+	require.Equal(t, filename, parseErr.Path)
+	// The path is reported once, by the parse error.
+	require.Equal(t, 1, strings.Count(err.Error(), filename))
+}
+
 var (
 	upAndDown = `package foo
 
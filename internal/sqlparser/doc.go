@@ -0,0 +1,4 @@
+// Package sqlparser implements the goose SQL migration parser. The public
+// github.com/pressly/goose/v3/sqlparser package exposes Parse, ParseFile and their types; the
+// remaining helpers are used by goose itself.
+package sqlparser
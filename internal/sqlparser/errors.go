@@ -0,0 +1,71 @@
+package sqlparser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParseError is returned when a SQL migration fails to parse. It records where in the file the
+// problem was found so that editors and CI tooling can point at the offending line.
+type ParseError struct {
+	// Path is the migration file path. It is empty when parsing from a reader that is not backed
+	// by a named file.
+	Path string
+	// Line is the 1-based line number the error refers to. For unterminated statements and
+	// unclosed StatementBegin blocks, this is the line where the statement or block started. It is
+	// 0 when the error is not tied to a specific line, such as an empty file.
+	Line int
+	// Column is the 1-based column of the first non-whitespace character on Line, or 0 if unknown.
+	Column int
+	// Annotation is the offending annotation, if any. For unrecognized annotations this is the raw
+	// annotation text, e.g., "-- +goose Foo".
+	Annotation string
+	// State is the readable name of the parser state when the error occurred, e.g., "up" or
+	// "down-statement-begin".
+	State string
+	// Direction is the direction being parsed when the error occurred.
+	Direction Direction
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	var b strings.Builder
+	if e.Path != "" {
+		b.WriteString(e.Path)
+	}
+	if e.Line > 0 {
+		if b.Len() > 0 {
+			b.WriteString(":")
+		} else {
+			b.WriteString("line ")
+		}
+		fmt.Fprintf(&b, "%d", e.Line)
+		if e.Column > 0 {
+			fmt.Fprintf(&b, ":%d", e.Column)
+		}
+	}
+	if b.Len() > 0 {
+		b.WriteString(": ")
+	}
+	b.WriteString(e.Err.Error())
+	if e.State != "" {
+		fmt.Fprintf(&b, " (state: %s)", e.State)
+	}
+	return b.String()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// WithPath records path on err if it is, or wraps, a [ParseError] without a path already set. The
+// error is returned unchanged otherwise.
+func WithPath(err error, path string) error {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) && parseErr.Path == "" {
+		parseErr.Path = path
+	}
+	return err
+}
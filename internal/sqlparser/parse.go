@@ -1,19 +1,138 @@
 package sqlparser
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"maps"
 
 	"go.uber.org/multierr"
 	"golang.org/x/sync/errgroup"
 )
 
+// Statement is a single SQL statement parsed from a migration file.
+type Statement struct {
+	// SQL is the statement text with surrounding whitespace removed. Custom delimiters are
+	// stripped and environment variables are substituted if ENVSUB was on.
+	SQL string
+	// Direction is the section of the file the statement belongs to.
+	Direction Direction
+	// StartLine and EndLine are the 1-based, inclusive range of source lines the statement was read
+	// from. Annotation lines are not included.
+	StartLine, EndLine int
+	// Envsub reports whether environment variable substitution was applied to any line of the
+	// statement.
+	Envsub bool
+	// Block reports whether the statement was wrapped in StatementBegin and StatementEnd
+	// annotations.
+	Block bool
+}
+
+// File is a parsed SQL migration file.
+type File struct {
+	// Path is the file path, empty when parsed with [Parse].
+	Path string
+	// UseTx is false if the file has a NO TRANSACTION annotation. It applies to both directions.
+	UseTx bool
+	// Up and Down are the statements of each section, in file order.
+	Up, Down []*Statement
+}
+
+// ParseOption configures how SQL migrations are parsed.
+type ParseOption interface {
+	apply(*parseConfig)
+}
+
+type parseConfig struct {
+	plsql bool
+	env   map[string]string
+}
+
+type parseConfigFunc func(*parseConfig)
+
+func (f parseConfigFunc) apply(cfg *parseConfig) {
+	f(cfg)
+}
+
+func newParseConfig(opts []ParseOption) parseConfig {
+	var cfg parseConfig
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	return cfg
+}
+
+// WithPLSQL parses Oracle migrations, where PL/SQL blocks are terminated by a slash on a line of
+// their own.
+func WithPLSQL() ParseOption {
+	return parseConfigFunc(func(c *parseConfig) {
+		c.plsql = true
+	})
+}
+
+// WithEnv adds variables for the ENVSUB annotation. They take precedence over environment
+// variables of the same name.
+func WithEnv(vars map[string]string) ParseOption {
+	return parseConfigFunc(func(c *parseConfig) {
+		if c.env == nil {
+			c.env = make(map[string]string, len(vars))
+		}
+		maps.Copy(c.env, vars)
+	})
+}
+
+// Parse parses a complete SQL migration from r, returning the statements of both directions.
+//
+// Errors in the file format, such as an unknown annotation or an unterminated statement, are
+// reported as a [*ParseError].
+func Parse(r io.Reader, opts ...ParseOption) (*File, error) {
+	by, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	cfg := newParseConfig(opts)
+	up, useTx, err := parseStatements(bytes.NewReader(by), DirectionUp, false, cfg)
+	if err != nil {
+		return nil, err
+	}
+	down, _, err := parseStatements(bytes.NewReader(by), DirectionDown, false, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &File{
+		UseTx: useTx,
+		Up:    up,
+		Down:  down,
+	}, nil
+}
+
+// ParseFile parses the SQL migration filename from fsys. See [Parse] for details.
+func ParseFile(fsys fs.FS, filename string, opts ...ParseOption) (_ *File, retErr error) {
+	r, err := fsys.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		retErr = multierr.Append(retErr, r.Close())
+	}()
+	f, err := Parse(r, opts...)
+	if err != nil {
+		return nil, WithPath(err, filename)
+	}
+	f.Path = filename
+	return f, nil
+}
+
+// ParsedSQL holds the statements of a SQL migration, as returned by [ParseAllFromFS].
 type ParsedSQL struct {
 	UseTx    bool
 	Up, Down []string
 }
 
-func ParseAllFromFS(fsys fs.FS, filename string, debug bool) (*ParsedSQL, error) {
+// ParseAllFromFS parses the SQL migration filename from fsys in both directions.
+func ParseAllFromFS(fsys fs.FS, filename string, debug bool, opts ...ParseOption) (*ParsedSQL, error) {
 	parsedSQL := new(ParsedSQL)
 	// TODO(mf): parse is called twice, once for up and once for down. This is inefficient. It
 	// should be possible to parse both directions in one pass. Also, UseTx is set once (but
@@ -21,7 +140,7 @@ func ParseAllFromFS(fsys fs.FS, filename string, debug bool) (*ParsedSQL, error)
 	// parseSQL disagree based on direction.
 	var g errgroup.Group
 	g.Go(func() error {
-		up, useTx, err := parse(fsys, filename, DirectionUp, debug)
+		up, useTx, err := parse(fsys, filename, DirectionUp, debug, opts)
 		if err != nil {
 			return err
 		}
@@ -30,7 +149,7 @@ func ParseAllFromFS(fsys fs.FS, filename string, debug bool) (*ParsedSQL, error)
 		return nil
 	})
 	g.Go(func() error {
-		down, _, err := parse(fsys, filename, DirectionDown, debug)
+		down, _, err := parse(fsys, filename, DirectionDown, debug, opts)
 		if err != nil {
 			return err
 		}
@@ -43,7 +162,13 @@ func ParseAllFromFS(fsys fs.FS, filename string, debug bool) (*ParsedSQL, error)
 	return parsedSQL, nil
 }
 
-func parse(fsys fs.FS, filename string, direction Direction, debug bool) (_ []string, _ bool, retErr error) {
+func parse(
+	fsys fs.FS,
+	filename string,
+	direction Direction,
+	debug bool,
+	opts []ParseOption,
+) (_ []string, _ bool, retErr error) {
 	r, err := fsys.Open(filename)
 	if err != nil {
 		return nil, false, err
@@ -51,8 +176,12 @@ func parse(fsys fs.FS, filename string, direction Direction, debug bool) (_ []st
 	defer func() {
 		retErr = multierr.Append(retErr, r.Close())
 	}()
-	stmts, useTx, err := ParseSQLMigration(r, direction, debug)
+	stmts, useTx, err := ParseSQLMigration(r, direction, debug, opts...)
 	if err != nil {
+		// A ParseError already carries the file path in its message.
+		if errors.As(err, new(*ParseError)) {
+			return nil, false, WithPath(err, filename)
+		}
 		return nil, false, fmt.Errorf("failed to parse %s: %w", filename, err)
 	}
 	return stmts, useTx, nil
@@ -26,6 +26,18 @@ func TestParseAllFromFS(t *testing.T) {
 		require.Contains(t, err.Error(), "failed to parse migration")
 		require.Contains(t, err.Error(), "must start with '-- +goose Up' annotation")
 	})
+	t.Run("parse_error_path", func(t *testing.T) {
+		mapFS := fstest.MapFS{
+			"001_foo.sql": newFile("-- +goose Up\nSELECT 1;\n-- +goose Unknown\n"),
+		}
+		_, err := sqlparser.ParseAllFromFS(mapFS, "001_foo.sql", false)
+		require.Error(t, err)
+		var parseErr *sqlparser.ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, "001_foo.sql", parseErr.Path)
+		require.Equal(t, 3, parseErr.Line)
+		require.Contains(t, err.Error(), "001_foo.sql:3:1:")
+	})
 	t.Run("all_statements", func(t *testing.T) {
 		mapFS := fstest.MapFS{
 			"001_foo.sql": newFile(`
@@ -14,6 +14,7 @@ import (
 	"github.com/mfridman/interpolate"
 )
 
+// Direction is the direction of a migration, either up or down.
 type Direction string
 
 const (
@@ -21,6 +22,7 @@ const (
 	DirectionDown Direction = "down"
 )
 
+// FromBool returns [DirectionUp] if b is true, otherwise [DirectionDown].
 func FromBool(b bool) Direction {
 	if b {
 		return DirectionUp
@@ -32,6 +34,7 @@ func (d Direction) String() string {
 	return string(d)
 }
 
+// ToBool returns true if d is [DirectionUp].
 func (d Direction) ToBool() bool {
 	return d == DirectionUp
 }
@@ -48,6 +51,28 @@ const (
 	gooseStatementEndDown                      // 6
 )
 
+// String returns a readable name for the parser state, used in error messages and debug output.
+func (s parserState) String() string {
+	switch s {
+	case start:
+		return "start"
+	case gooseUp:
+		return "up"
+	case gooseStatementBeginUp:
+		return "up-statement-begin"
+	case gooseStatementEndUp:
+		return "up-statement-end"
+	case gooseDown:
+		return "down"
+	case gooseStatementBeginDown:
+		return "down-statement-begin"
+	case gooseStatementEndDown:
+		return "down-statement-end"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
 type stateMachine struct {
 	state   parserState
 	verbose bool
@@ -65,7 +90,7 @@ func (s *stateMachine) get() parserState {
 }
 
 func (s *stateMachine) set(new parserState) {
-	s.print("set %d => %d", s.state, new)
+	s.print("set %s => %s", s.state, new)
 	s.state = new
 }
 
@@ -90,8 +115,9 @@ var bufferPool = sync.Pool{
 	},
 }
 
-// Split given SQL script into individual statements and return
-// SQL statements for given direction (up=true, down=false).
+// ParseSQLMigration splits the given SQL script into individual statements and returns the SQL
+// statements for the given direction, along with whether the migration should run in a
+// transaction. If debug is true, the parser logs each line and state transition.
 //
 // The base case is to simply split on semicolons, as these
 // naturally terminate a statement.
@@ -100,7 +126,21 @@ var bufferPool = sync.Pool{
 // within a statement. For these cases, we provide the explicit annotations
 // 'StatementBegin' and 'StatementEnd' to allow the script to
 // tell us to ignore semicolons.
-func ParseSQLMigration(r io.Reader, direction Direction, debug bool) (stmts []string, useTx bool, err error) {
+//
+// Use [Parse] to also get line ranges and other metadata for each statement, and [WithPLSQL] for
+// Oracle migrations.
+func ParseSQLMigration(r io.Reader, direction Direction, debug bool, opts ...ParseOption) (stmts []string, useTx bool, err error) {
+	statements, useTx, err := parseStatements(r, direction, debug, newParseConfig(opts))
+	if err != nil {
+		return nil, false, err
+	}
+	for _, stmt := range statements {
+		stmts = append(stmts, stmt.SQL)
+	}
+	return stmts, useTx, nil
+}
+
+func parseStatements(r io.Reader, direction Direction, debug bool, cfg parseConfig) (stmts []*Statement, useTx bool, err error) {
 	scanBufPtr := bufferPool.Get().(*[]byte)
 	scanBuf := *scanBufPtr
 	defer bufferPool.Put(scanBufPtr)
@@ -109,12 +149,68 @@ func ParseSQLMigration(r io.Reader, direction Direction, debug bool) (stmts []st
 	scanner.Buffer(scanBuf, scanBufSize)
 
 	stateMachine := newStateMachine(start, debug)
+	var buf bytes.Buffer
 	useTx = true
 	useEnvsub := false
+	// delimiter is the statement terminator outside of StatementBegin/StatementEnd blocks. It may
+	// be changed with the "-- +goose DELIMITER" annotation, similar to the mysql client.
+	delimiter := defaultDelimiter
+	// plsqlBlock is set when the statement held in buf is a PL/SQL block, which is terminated by a
+	// slash on a line of its own rather than by a semicolon. Only used with WithPLSQL.
+	plsqlBlock := false
+	// plsqlHeader holds the leading words of the statement in buf while they do not tell yet
+	// whether it is a PL/SQL block, e.g., "CREATE OR REPLACE" with the object type on the next
+	// line. It is nil once that is decided.
+	var plsqlHeader []string
+
+	// lineNum is the 1-based number of the line currently being processed. stmtLine and stmtText
+	// record where the statement currently held in buf started, and beginLine records the line of
+	// the most recent StatementBegin annotation. These are used to point errors at the line that
+	// caused them, rather than the line the parser happened to be on when it noticed.
+	//
+	// stmtEndLine and stmtEnvsub track the last buffered line and whether variable substitution was
+	// applied to any line of the current statement; they are reported on each [Statement].
+	var (
+		lineNum     int
+		stmtLine    int
+		stmtText    string
+		stmtEndLine int
+		stmtEnvsub  bool
+		beginLine   int
+		beginText   string
+	)
+	storeStatement := func(block bool) {
+		// A custom delimiter on a line of its own may leave nothing behind.
+		if sql := cleanupStatement(buf.String()); sql != "" || block {
+			stmts = append(stmts, &Statement{
+				SQL:       sql,
+				Direction: direction,
+				StartLine: stmtLine,
+				EndLine:   stmtEndLine,
+				Envsub:    stmtEnvsub,
+				Block:     block,
+			})
+		}
+		buf.Reset()
+		plsqlBlock = false
+		plsqlHeader = nil
+	}
+	newError := func(line int, text string, ann Annotation, format string, args ...any) *ParseError {
+		return &ParseError{
+			Line:       line,
+			Column:     firstColumn(text),
+			Annotation: string(ann),
+			State:      stateMachine.get().String(),
+			Direction:  direction,
+			Err:        fmt.Errorf(format, args...),
+		}
+	}
 
-	var buf bytes.Buffer
 	for scanner.Scan() {
 		line := scanner.Text()
+		lineNum++
+		// terminated is set when a custom delimiter ends the statement on this line.
+		terminated := false
 		if debug {
 			log.Println(line)
 		}
@@ -125,73 +221,97 @@ func ParseSQLMigration(r io.Reader, direction Direction, debug bool) (stmts []st
 		// Check for annotations.
 		// All annotations must be in format: "-- +goose [annotation]"
 		if strings.HasPrefix(strings.TrimSpace(line), "--") && strings.Contains(line, "+goose") {
-			var cmd annotation
+			var cmd Annotation
 
-			cmd, err = extractAnnotation(line)
+			cmd, err = ExtractAnnotation(line)
 			if err != nil {
-				return nil, false, fmt.Errorf("failed to parse annotation line %q: %w", line, err)
+				e := newError(lineNum, line, "", "failed to parse annotation line %q: %w", line, err)
+				e.Annotation = strings.TrimSpace(line)
+				return nil, false, e
 			}
 
 			switch cmd {
-			case annotationUp:
+			case AnnotationUp:
 				switch stateMachine.get() {
 				case start:
 					stateMachine.set(gooseUp)
 				default:
-					return nil, false, fmt.Errorf("duplicate '-- +goose Up' annotations; stateMachine=%d, see https://github.com/pressly/goose#sql-migrations", stateMachine.state)
+					return nil, false, newError(lineNum, line, cmd, "duplicate '-- +goose Up' annotations, see https://github.com/pressly/goose#sql-migrations")
 				}
 				continue
 
-			case annotationDown:
+			case AnnotationDown:
 				switch stateMachine.get() {
 				case gooseUp, gooseStatementEndUp:
 					// If we hit a down annotation, but the buffer is not empty, we have an unfinished SQL query from a
 					// previous up annotation. This is an error, because we expect the SQL query to be terminated by a semicolon
 					// and the buffer to have been reset.
 					if bufferRemaining := strings.TrimSpace(buf.String()); len(bufferRemaining) > 0 {
-						return nil, false, missingSemicolonError(stateMachine.state, direction, bufferRemaining)
+						e := missingSemicolonError(stateMachine.state, direction, terminator(delimiter, plsqlBlock), bufferRemaining)
+						e.Line, e.Column = stmtLine, firstColumn(stmtText)
+						return nil, false, e
 					}
 					stateMachine.set(gooseDown)
 				default:
-					return nil, false, fmt.Errorf("must start with '-- +goose Up' annotation, stateMachine=%d, see https://github.com/pressly/goose#sql-migrations", stateMachine.state)
+					return nil, false, newError(lineNum, line, cmd, "must start with '-- +goose Up' annotation, see https://github.com/pressly/goose#sql-migrations")
 				}
 				continue
 
-			case annotationStatementBegin:
+			case AnnotationStatementBegin:
 				switch stateMachine.get() {
 				case gooseUp, gooseStatementEndUp:
 					stateMachine.set(gooseStatementBeginUp)
 				case gooseDown, gooseStatementEndDown:
 					stateMachine.set(gooseStatementBeginDown)
 				default:
-					return nil, false, fmt.Errorf("'-- +goose StatementBegin' must be defined after '-- +goose Up' or '-- +goose Down' annotation, stateMachine=%d, see https://github.com/pressly/goose#sql-migrations", stateMachine.state)
+					return nil, false, newError(lineNum, line, cmd, "'-- +goose StatementBegin' must be defined after '-- +goose Up' or '-- +goose Down' annotation, see https://github.com/pressly/goose#sql-migrations")
 				}
+				beginLine, beginText = lineNum, line
 				continue
 
-			case annotationStatementEnd:
+			case AnnotationStatementEnd:
 				switch stateMachine.get() {
 				case gooseStatementBeginUp:
 					stateMachine.set(gooseStatementEndUp)
 				case gooseStatementBeginDown:
 					stateMachine.set(gooseStatementEndDown)
 				default:
-					return nil, false, errors.New("'-- +goose StatementEnd' must be defined after '-- +goose StatementBegin', see https://github.com/pressly/goose#sql-migrations")
+					return nil, false, newError(lineNum, line, cmd, "'-- +goose StatementEnd' must be defined after '-- +goose StatementBegin', see https://github.com/pressly/goose#sql-migrations")
 				}
 
-			case annotationNoTransaction:
+			case AnnotationNoTransaction:
 				useTx = false
 				continue
 
-			case annotationEnvsubOn:
+			case AnnotationEnvsubOn:
 				useEnvsub = true
 				continue
 
-			case annotationEnvsubOff:
+			case AnnotationEnvsubOff:
 				useEnvsub = false
 				continue
 
+			case AnnotationLintIgnore:
+				continue
+
+			case AnnotationDelimiter:
+				switch stateMachine.get() {
+				case gooseStatementBeginUp, gooseStatementBeginDown:
+					return nil, false, newError(lineNum, line, cmd, "'-- +goose DELIMITER' must not be used between '-- +goose StatementBegin' and '-- +goose StatementEnd'")
+				}
+				if bufferRemaining := strings.TrimSpace(buf.String()); len(bufferRemaining) > 0 {
+					return nil, false, newError(stmtLine, stmtText, cmd, "'-- +goose DELIMITER' found before the previous statement was terminated by %q", delimiter)
+				}
+				d, err := extractDelimiter(line)
+				if err != nil {
+					return nil, false, newError(lineNum, line, cmd, "failed to parse annotation line %q: %w", line, err)
+				}
+				stateMachine.print("set delimiter %q", d)
+				delimiter = d
+				continue
+
 			default:
-				return nil, false, fmt.Errorf("unknown annotation: %q", cmd)
+				return nil, false, newError(lineNum, line, cmd, "unknown annotation: %q", cmd)
 			}
 		}
 		// Once we've started parsing a statement the buffer is no longer empty,
@@ -204,17 +324,65 @@ func ParseSQLMigration(r io.Reader, direction Direction, debug bool) (stmts []st
 				continue
 			}
 		}
+		// In SQL*Plus, a slash on a line of its own terminates the statement, usually a PL/SQL block.
+		// It is not part of the statement, including at the end of a StatementBegin block.
+		if cfg.plsql && delimiter == defaultDelimiter && strings.TrimSpace(line) == "/" {
+			switch stateMachine.get() {
+			case gooseUp, gooseDown:
+				if buf.Len() > 0 {
+					storeStatement(false)
+					stateMachine.print("store slash-terminated query")
+				}
+				continue
+			case gooseStatementBeginUp, gooseStatementBeginDown:
+				continue
+			}
+		}
 		switch stateMachine.get() {
 		case gooseStatementEndDown, gooseStatementEndUp:
 			// Do not include the "+goose StatementEnd" annotation in the final statement.
 		default:
 			if useEnvsub {
-				expanded, err := interpolate.Interpolate(&envWrapper{}, line)
+				expanded, err := interpolate.Interpolate(&envWrapper{vars: cfg.env}, line)
 				if err != nil {
-					return nil, false, fmt.Errorf("variable substitution failed: %w:\n%s", err, line)
+					return nil, false, newError(lineNum, line, "", "variable substitution failed: %w:\n%s", err, line)
 				}
 				line = expanded
 			}
+			if buf.Len() == 0 {
+				stmtLine, stmtText = lineNum, line
+				stmtEnvsub = false
+				plsqlHeader = nil
+				switch stateMachine.get() {
+				case gooseUp, gooseDown:
+					if cfg.plsql && delimiter == defaultDelimiter {
+						plsqlHeader = []string{}
+					}
+				}
+			}
+			if plsqlHeader != nil {
+				words := append(plsqlHeader, plsqlWords(line)...)
+				var decided bool
+				if plsqlBlock, decided = isPLSQLBlock(words); decided {
+					plsqlHeader = nil
+				} else {
+					plsqlHeader = words
+				}
+			}
+			stmtEndLine = lineNum
+			stmtEnvsub = stmtEnvsub || useEnvsub
+			// With a custom delimiter, the delimiter itself is not part of the statement and is
+			// removed before the line is buffered, just like the mysql client does. Oracle rejects
+			// the semicolon at the end of a SQL statement, so WithPLSQL removes it the same way.
+			if stripsDelimiter(delimiter, cfg, plsqlBlock) {
+				switch stateMachine.get() {
+				case gooseUp, gooseDown:
+					if i := delimiterIndex(line, delimiter); i >= 0 {
+						line = line[:i] + line[i+len(delimiter):]
+						terminated = true
+					}
+				}
+			}
 			// Write SQL line to a buffer.
 			if _, err := buf.WriteString(line + "\n"); err != nil {
 				return nil, false, fmt.Errorf("failed to write to buf: %w", err)
@@ -239,30 +407,26 @@ func ParseSQLMigration(r io.Reader, direction Direction, debug bool) (stmts []st
 				continue
 			}
 		default:
-			return nil, false, fmt.Errorf("failed to parse migration: unexpected state %d on line %q, see https://github.com/pressly/goose#sql-migrations", stateMachine.state, line)
+			return nil, false, newError(lineNum, line, "", "failed to parse migration: unexpected state on line %q, see https://github.com/pressly/goose#sql-migrations", line)
 		}
 
 		switch stateMachine.get() {
 		case gooseUp:
-			if endsWithSemicolon(line) {
-				stmts = append(stmts, cleanupStatement(buf.String()))
-				buf.Reset()
+			if !plsqlBlock && endsWithDelimiter(line, stripsDelimiter(delimiter, cfg, false), terminated) {
+				storeStatement(false)
 				stateMachine.print("store simple Up query")
 			}
 		case gooseDown:
-			if endsWithSemicolon(line) {
-				stmts = append(stmts, cleanupStatement(buf.String()))
-				buf.Reset()
+			if !plsqlBlock && endsWithDelimiter(line, stripsDelimiter(delimiter, cfg, false), terminated) {
+				storeStatement(false)
 				stateMachine.print("store simple Down query")
 			}
 		case gooseStatementEndUp:
-			stmts = append(stmts, cleanupStatement(buf.String()))
-			buf.Reset()
+			storeStatement(true)
 			stateMachine.print("store Up statement")
 			stateMachine.set(gooseUp)
 		case gooseStatementEndDown:
-			stmts = append(stmts, cleanupStatement(buf.String()))
-			buf.Reset()
+			storeStatement(true)
 			stateMachine.print("store Down statement")
 			stateMachine.set(gooseDown)
 		}
@@ -274,38 +438,50 @@ func ParseSQLMigration(r io.Reader, direction Direction, debug bool) (stmts []st
 
 	switch stateMachine.get() {
 	case start:
-		return nil, false, errors.New("failed to parse migration: must start with '-- +goose Up' annotation, see https://github.com/pressly/goose#sql-migrations")
+		return nil, false, newError(0, "", "", "failed to parse migration: must start with '-- +goose Up' annotation, see https://github.com/pressly/goose#sql-migrations")
 	case gooseStatementBeginUp, gooseStatementBeginDown:
-		return nil, false, errors.New("failed to parse migration: missing '-- +goose StatementEnd' annotation")
+		return nil, false, newError(beginLine, beginText, AnnotationStatementBegin, "failed to parse migration: missing '-- +goose StatementEnd' annotation")
 	}
 
 	if bufferRemaining := strings.TrimSpace(buf.String()); len(bufferRemaining) > 0 {
-		return nil, false, missingSemicolonError(stateMachine.state, direction, bufferRemaining)
+		e := missingSemicolonError(stateMachine.state, direction, terminator(delimiter, plsqlBlock), bufferRemaining)
+		e.Line, e.Column = stmtLine, firstColumn(stmtText)
+		return nil, false, e
 	}
 
 	return stmts, useTx, nil
 }
 
-type annotation string
+// Annotation is a goose annotation in a SQL migration file, written as "-- +goose [annotation]".
+type Annotation string
 
 const (
-	annotationUp             annotation = "Up"
-	annotationDown           annotation = "Down"
-	annotationStatementBegin annotation = "StatementBegin"
-	annotationStatementEnd   annotation = "StatementEnd"
-	annotationNoTransaction  annotation = "NO TRANSACTION"
-	annotationEnvsubOn       annotation = "ENVSUB ON"
-	annotationEnvsubOff      annotation = "ENVSUB OFF"
+	AnnotationUp             Annotation = "Up"
+	AnnotationDown           Annotation = "Down"
+	AnnotationStatementBegin Annotation = "StatementBegin"
+	AnnotationStatementEnd   Annotation = "StatementEnd"
+	AnnotationNoTransaction  Annotation = "NO TRANSACTION"
+	AnnotationEnvsubOn       Annotation = "ENVSUB ON"
+	AnnotationEnvsubOff      Annotation = "ENVSUB OFF"
+	// AnnotationDelimiter takes an argument, e.g., "-- +goose DELIMITER //", and is therefore not
+	// part of supportedAnnotations.
+	AnnotationDelimiter Annotation = "DELIMITER"
+	// AnnotationLintIgnore suppresses the listed lint rules for the whole file, e.g., "-- +goose
+	// LINT IGNORE drop-table". It is accepted and ignored by the parser; see the lint package.
+	AnnotationLintIgnore Annotation = "LINT IGNORE"
 )
 
-var supportedAnnotations = map[annotation]struct{}{
-	annotationUp:             {},
-	annotationDown:           {},
-	annotationStatementBegin: {},
-	annotationStatementEnd:   {},
-	annotationNoTransaction:  {},
-	annotationEnvsubOn:       {},
-	annotationEnvsubOff:      {},
+// defaultDelimiter is the statement terminator used unless changed with a DELIMITER annotation.
+const defaultDelimiter = ";"
+
+var supportedAnnotations = map[Annotation]struct{}{
+	AnnotationUp:             {},
+	AnnotationDown:           {},
+	AnnotationStatementBegin: {},
+	AnnotationStatementEnd:   {},
+	AnnotationNoTransaction:  {},
+	AnnotationEnvsubOn:       {},
+	AnnotationEnvsubOff:      {},
 }
 
 var (
@@ -313,10 +489,11 @@ var (
 	errInvalidAnnotation = errors.New("invalid annotation")
 )
 
-// extractAnnotation extracts the annotation from the line.
+// ExtractAnnotation extracts the annotation from the line.
 // All annotations must be in format: "-- +goose [annotation]"
-// Allowed annotations: Up, Down, StatementBegin, StatementEnd, NO TRANSACTION, ENVSUB ON, ENVSUB OFF
-func extractAnnotation(line string) (annotation, error) {
+// Allowed annotations: Up, Down, StatementBegin, StatementEnd, NO TRANSACTION, ENVSUB ON, ENVSUB OFF,
+// DELIMITER [delimiter], LINT IGNORE [rules]
+func ExtractAnnotation(line string) (Annotation, error) {
 	// If line contains leading whitespace - return error.
 	if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
 		return "", fmt.Errorf("%q contains leading whitespace: %w", line, errInvalidAnnotation)
@@ -339,7 +516,16 @@ func extractAnnotation(line string) (annotation, error) {
 		return "", errEmptyAnnotation
 	}
 
-	a := annotation(cmd)
+	// Annotations that take arguments.
+	fields := strings.Fields(cmd)
+	if strings.EqualFold(fields[0], string(AnnotationDelimiter)) {
+		return AnnotationDelimiter, nil
+	}
+	if len(fields) >= 2 && strings.EqualFold(fields[0]+" "+fields[1], string(AnnotationLintIgnore)) {
+		return AnnotationLintIgnore, nil
+	}
+
+	a := Annotation(cmd)
 
 	for s := range supportedAnnotations {
 		if strings.EqualFold(string(s), string(a)) {
@@ -350,19 +536,55 @@ func extractAnnotation(line string) (annotation, error) {
 	return "", fmt.Errorf("%q not supported: %w", cmd, errInvalidAnnotation)
 }
 
-func missingSemicolonError(state parserState, direction Direction, s string) error {
-	return fmt.Errorf("failed to parse migration: state %d, direction: %v: unexpected unfinished SQL query: %q: missing semicolon?",
-		state,
-		direction,
-		s,
-	)
+func missingSemicolonError(state parserState, direction Direction, delimiter string, s string) *ParseError {
+	hint := "missing semicolon?"
+	if delimiter != defaultDelimiter {
+		hint = fmt.Sprintf("missing delimiter %q?", delimiter)
+	}
+	return &ParseError{
+		State:     state.String(),
+		Direction: direction,
+		Err: fmt.Errorf("failed to parse migration: direction: %v: unexpected unfinished SQL query: %q: %s",
+			direction,
+			s,
+			hint,
+		),
+	}
 }
 
-type envWrapper struct{}
+// extractDelimiter returns the delimiter from a "-- +goose DELIMITER [delimiter]" annotation line.
+func extractDelimiter(line string) (string, error) {
+	_, after, _ := strings.Cut(line, "+goose")
+	fields := strings.Fields(after)
+	if len(fields) != 2 {
+		return "", fmt.Errorf("DELIMITER requires exactly one argument: %w", errInvalidAnnotation)
+	}
+	delimiter := fields[1]
+	if strings.HasPrefix(delimiter, "--") {
+		return "", fmt.Errorf("delimiter %q must not start with a comment: %w", delimiter, errInvalidAnnotation)
+	}
+	return delimiter, nil
+}
+
+// firstColumn returns the 1-based column of the first non-whitespace character in line, or 0 if
+// the line is empty.
+func firstColumn(line string) int {
+	if i := strings.IndexFunc(line, func(r rune) bool { return r != ' ' && r != '\t' }); i >= 0 {
+		return i + 1
+	}
+	return 0
+}
+
+type envWrapper struct {
+	vars map[string]string
+}
 
 var _ interpolate.Env = (*envWrapper)(nil)
 
 func (e *envWrapper) Get(key string) (string, bool) {
+	if v, ok := e.vars[key]; ok {
+		return v, true
+	}
 	return os.LookupEnv(key)
 }
 
@@ -370,6 +592,91 @@ func cleanupStatement(input string) string {
 	return strings.TrimSpace(input)
 }
 
+// endsWithDelimiter reports whether line terminates a statement. For the default delimiter this
+// is a trailing semicolon; delimiters that are stripped are detected before the line is buffered,
+// so terminated is returned as-is.
+func endsWithDelimiter(line string, stripped bool, terminated bool) bool {
+	if !stripped {
+		return endsWithSemicolon(line)
+	}
+	return terminated
+}
+
+// stripsDelimiter reports whether the delimiter is removed from the end of a statement: custom
+// delimiters always are, and semicolons with WithPLSQL, except in PL/SQL blocks.
+func stripsDelimiter(delimiter string, cfg parseConfig, plsqlBlock bool) bool {
+	return delimiter != defaultDelimiter || (cfg.plsql && !plsqlBlock)
+}
+
+// terminator returns the terminator that the statement held in the buffer is missing, for errors.
+func terminator(delimiter string, plsqlBlock bool) string {
+	if plsqlBlock {
+		return "/"
+	}
+	return delimiter
+}
+
+// plsqlWords returns the upper-cased words of line before any double-dash comment.
+func plsqlWords(line string) []string {
+	line, _, _ = strings.Cut(line, "--")
+	return strings.Fields(strings.ToUpper(line))
+}
+
+// isPLSQLBlock reports whether a statement starting with words is a PL/SQL block, which SQL*Plus
+// terminates with a slash: an anonymous block or the creation of a stored program unit or type.
+// decided is false if the words end before the type of the created object, which may follow on the
+// next line.
+func isPLSQLBlock(words []string) (block, decided bool) {
+	if len(words) == 0 {
+		return false, false
+	}
+	switch words[0] {
+	case "BEGIN", "DECLARE":
+		return true, true
+	case "CREATE":
+	default:
+		return false, true
+	}
+	words = words[1:]
+	if len(words) > 0 && words[0] == "OR" {
+		if len(words) == 1 {
+			return false, false
+		}
+		if words[1] == "REPLACE" {
+			words = words[2:]
+		}
+	}
+	if len(words) > 0 && (words[0] == "EDITIONABLE" || words[0] == "NONEDITIONABLE") {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return false, false
+	}
+	switch words[0] {
+	case "FUNCTION", "PROCEDURE", "PACKAGE", "TRIGGER", "TYPE", "LIBRARY", "JAVA":
+		return true, true
+	}
+	return false, true
+}
+
+// delimiterIndex returns the byte offset of delimiter in line if the last word before any
+// double-dash comment ends with it, otherwise -1.
+func delimiterIndex(line string, delimiter string) int {
+	index := -1
+	var pos int
+	for _, word := range strings.Fields(line) {
+		if strings.HasPrefix(word, "--") {
+			break
+		}
+		pos += strings.Index(line[pos:], word) + len(word)
+		index = -1
+		if strings.HasSuffix(word, delimiter) {
+			index = pos - len(delimiter)
+		}
+	}
+	return index
+}
+
 // Checks the line to see if the line has a statement-ending semicolon
 // or if the line contains a double-dash comment.
 func endsWithSemicolon(line string) bool {
@@ -1,6 +1,7 @@
 package sqlparser
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -374,7 +375,7 @@ func TestValidUp(t *testing.T) {
 	t.Parallel()
 	// Test valid "up" parser logic.
 	//
-	// This test expects each directory, such as: internal/sqlparser/testdata/valid-up/test01
+	// This test expects each directory, such as: sqlparser/testdata/valid-up/test01
 	//
 	// to contain exactly one migration file called "input.sql". We read this file and pass it
 	// to the parser. Then we compare the statements against the golden files.
@@ -397,6 +398,7 @@ func TestValidUp(t *testing.T) {
 		{Name: "test07", StatementsCount: 1},
 		{Name: "test08", StatementsCount: 6},
 		{Name: "test09", StatementsCount: 1},
+		{Name: "test10", StatementsCount: 4},
 	}
 	for _, tc := range tests {
 		path := filepath.Join("testdata", "valid-up", tc.Name)
@@ -448,8 +450,8 @@ func compareStatements(t *testing.T, dir string, statements []string, direction
 			} else {
 				t.Error("input does not match expected output; diff files with .FAIL to debug")
 				t.Logf("\ndiff %v %v",
-					filepath.Join("internal", "sqlparser", goldenFilePath+".FAIL"),
-					filepath.Join("internal", "sqlparser", goldenFilePath),
+					filepath.Join("sqlparser", goldenFilePath+".FAIL"),
+					filepath.Join("sqlparser", goldenFilePath),
 				)
 				err := os.WriteFile(goldenFilePath+".FAIL", []byte(got), 0644)
 				require.NoError(t, err)
@@ -490,6 +492,25 @@ func TestEnvsub(t *testing.T) {
 	}
 }
 
+func TestWithEnv(t *testing.T) {
+	t.Setenv("GOOSE_ENV_NAME", "foo")
+
+	s := `
+-- +goose ENVSUB ON
+-- +goose Up
+CREATE TABLE ${GOOSE_ENV_NAME} ${GOOSE_ON_CLUSTER} (id Int64) ENGINE = MergeTree ORDER BY id;
+`
+	stmts, _, err := ParseSQLMigration(strings.NewReader(s), DirectionUp, debug,
+		WithEnv(map[string]string{"GOOSE_ON_CLUSTER": "ON CLUSTER 'main'"}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"CREATE TABLE foo ON CLUSTER 'main' (id Int64) ENGINE = MergeTree ORDER BY id;"}, stmts)
+	// Variables given to WithEnv take precedence over the environment.
+	stmts, _, err = ParseSQLMigration(strings.NewReader(s), DirectionUp, debug,
+		WithEnv(map[string]string{"GOOSE_ENV_NAME": "bar", "GOOSE_ON_CLUSTER": ""}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"CREATE TABLE bar  (id Int64) ENGINE = MergeTree ORDER BY id;"}, stmts)
+}
+
 func TestEnvsubError(t *testing.T) {
 	t.Parallel()
 
@@ -512,31 +533,49 @@ func Test_extractAnnotation(t *testing.T) {
 	tests := []struct {
 		name    string
 		input   string
-		want    annotation
+		want    Annotation
 		wantErr bool
 	}{
 		{
 			name:    "Up",
 			input:   "-- +goose Up",
-			want:    annotationUp,
+			want:    AnnotationUp,
 			wantErr: false,
 		},
 		{
 			name:    "Down",
 			input:   "-- +goose Down",
-			want:    annotationDown,
+			want:    AnnotationDown,
 			wantErr: false,
 		},
 		{
 			name:    "StmtBegin",
 			input:   "-- +goose StatementBegin",
-			want:    annotationStatementBegin,
+			want:    AnnotationStatementBegin,
 			wantErr: false,
 		},
 		{
 			name:    "NoTransact",
 			input:   "-- +goose NO TRANSACTION",
-			want:    annotationNoTransaction,
+			want:    AnnotationNoTransaction,
+			wantErr: false,
+		},
+		{
+			name:    "Delimiter",
+			input:   "-- +goose DELIMITER //",
+			want:    AnnotationDelimiter,
+			wantErr: false,
+		},
+		{
+			name:    "Delimiter lowercase",
+			input:   "-- +goose delimiter ;",
+			want:    AnnotationDelimiter,
+			wantErr: false,
+		},
+		{
+			name:    "LintIgnore",
+			input:   "-- +goose LINT IGNORE drop-table,drop-column",
+			want:    AnnotationLintIgnore,
 			wantErr: false,
 		},
 		{
@@ -554,7 +593,7 @@ func Test_extractAnnotation(t *testing.T) {
 		{
 			name:    "statement with spaces and Uppercase",
 			input:   "-- +goose   UP 	",
-			want:    annotationUp,
+			want:    AnnotationUp,
 			wantErr: false,
 		},
 		{
@@ -579,7 +618,7 @@ func Test_extractAnnotation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := extractAnnotation(tt.input)
+			got, err := ExtractAnnotation(tt.input)
 			if tt.wantErr {
 				require.Error(t, err)
 			} else {
@@ -589,3 +628,248 @@ func Test_extractAnnotation(t *testing.T) {
 		})
 	}
 }
+
+func TestParseError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		sql        string
+		line       int
+		column     int
+		annotation string
+		state      string
+		contains   string
+	}{
+		{
+			name:       "unknown_annotation",
+			sql:        "-- +goose Up\nSELECT 1;\n-- +goose Foo\n",
+			line:       3,
+			column:     1,
+			annotation: "-- +goose Foo",
+			state:      "up",
+			contains:   "not supported",
+		},
+		{
+			name:       "duplicate_up",
+			sql:        "-- +goose Up\nSELECT 1;\n\n-- +goose Up\n",
+			line:       4,
+			column:     1,
+			annotation: "Up",
+			state:      "up",
+			contains:   "duplicate '-- +goose Up' annotations",
+		},
+		{
+			name:       "missing_statement_end",
+			sql:        "-- +goose Up\nSELECT 1;\n-- +goose StatementBegin\nSELECT 2;\n",
+			line:       3,
+			column:     1,
+			annotation: "StatementBegin",
+			state:      "up-statement-begin",
+			contains:   "missing '-- +goose StatementEnd' annotation",
+		},
+		{
+			name:     "unterminated_statement",
+			sql:      "-- +goose Up\nSELECT 1;\n  ALTER TABLE post\n  ADD COLUMN foo text\n-- +goose Down\n",
+			line:     3,
+			column:   3,
+			state:    "up",
+			contains: "missing semicolon?",
+		},
+		{
+			name:     "empty",
+			sql:      "",
+			line:     0,
+			state:    "start",
+			contains: "must start with '-- +goose Up' annotation",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := ParseSQLMigration(strings.NewReader(tc.sql), DirectionUp, debug)
+			require.Error(t, err)
+			var parseErr *ParseError
+			require.ErrorAs(t, err, &parseErr)
+			require.Equal(t, tc.line, parseErr.Line)
+			require.Equal(t, tc.column, parseErr.Column)
+			require.Equal(t, tc.annotation, parseErr.Annotation)
+			require.Equal(t, tc.state, parseErr.State)
+			require.Equal(t, DirectionUp, parseErr.Direction)
+			require.Contains(t, err.Error(), tc.contains)
+			require.NotContains(t, err.Error(), "stateMachine=")
+		})
+	}
+	t.Run("path", func(t *testing.T) {
+		err := WithPath(&ParseError{Line: 4, Column: 2, State: "up", Err: errors.New("boom")}, "migrations/001_foo.sql")
+		require.EqualError(t, err, "migrations/001_foo.sql:4:2: boom (state: up)")
+	})
+}
+
+func TestDelimiter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("down", func(t *testing.T) {
+		testValid(t, filepath.Join("testdata", "valid-up", "test10"), 3, DirectionDown)
+	})
+	t.Run("delimiter_only_line", func(t *testing.T) {
+		s := "-- +goose Up\n-- +goose DELIMITER //\nSELECT 1\n//\n//\n-- +goose DELIMITER ;\n"
+		stmts, _, err := ParseSQLMigration(strings.NewReader(s), DirectionUp, debug)
+		require.NoError(t, err)
+		require.Equal(t, []string{"SELECT 1"}, stmts)
+	})
+	t.Run("statement_begin_ignores_delimiter", func(t *testing.T) {
+		s := "-- +goose Up\n-- +goose DELIMITER //\n-- +goose StatementBegin\nSELECT 1 //\nSELECT 2 //\n-- +goose StatementEnd\n"
+		stmts, _, err := ParseSQLMigration(strings.NewReader(s), DirectionUp, debug)
+		require.NoError(t, err)
+		require.Equal(t, []string{"SELECT 1 //\nSELECT 2 //"}, stmts)
+	})
+	errorTests := []struct {
+		name     string
+		sql      string
+		line     int
+		contains string
+	}{
+		{
+			name:     "missing_argument",
+			sql:      "-- +goose Up\n-- +goose DELIMITER\n",
+			line:     2,
+			contains: "DELIMITER requires exactly one argument",
+		},
+		{
+			name:     "inside_statement_begin",
+			sql:      "-- +goose Up\n-- +goose StatementBegin\n-- +goose DELIMITER //\n",
+			line:     3,
+			contains: "must not be used between",
+		},
+		{
+			name:     "unterminated_before_change",
+			sql:      "-- +goose Up\nSELECT 1\n-- +goose DELIMITER //\n",
+			line:     2,
+			contains: "before the previous statement was terminated",
+		},
+		{
+			name:     "unterminated_custom",
+			sql:      "-- +goose Up\n-- +goose DELIMITER //\nSELECT 1;\n",
+			line:     3,
+			contains: `missing delimiter "//"?`,
+		},
+	}
+	for _, tc := range errorTests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := ParseSQLMigration(strings.NewReader(tc.sql), DirectionUp, debug)
+			require.Error(t, err)
+			var parseErr *ParseError
+			require.ErrorAs(t, err, &parseErr)
+			require.Equal(t, tc.line, parseErr.Line)
+			require.Contains(t, err.Error(), tc.contains)
+		})
+	}
+}
+
+func TestPLSQL(t *testing.T) {
+	t.Parallel()
+
+	const migration = `-- +goose Up
+CREATE TABLE post (
+  id NUMBER PRIMARY KEY,
+  updated_at TIMESTAMP
+); -- plain statements end with a semicolon
+
+CREATE OR REPLACE PROCEDURE touch_post(p_id NUMBER) AS
+BEGIN
+  UPDATE post SET updated_at = SYSTIMESTAMP WHERE id = p_id;
+END;
+/
+
+BEGIN
+  touch_post(1);
+END;
+/
+/
+
+-- +goose StatementBegin
+create or replace trigger post_touch
+before update on post for each row
+begin
+  :new.updated_at := systimestamp;
+end;
+/
+-- +goose StatementEnd
+
+INSERT INTO post (id) VALUES (1)
+/
+
+-- +goose Down
+DROP TRIGGER post_touch;
+DROP PROCEDURE touch_post;
+DROP TABLE post;
+`
+	up, _, err := ParseSQLMigration(strings.NewReader(migration), DirectionUp, debug, WithPLSQL())
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"CREATE TABLE post (\n  id NUMBER PRIMARY KEY,\n  updated_at TIMESTAMP\n) -- plain statements end with a semicolon",
+		"CREATE OR REPLACE PROCEDURE touch_post(p_id NUMBER) AS\nBEGIN\n  UPDATE post SET updated_at = SYSTIMESTAMP WHERE id = p_id;\nEND;",
+		"BEGIN\n  touch_post(1);\nEND;",
+		"create or replace trigger post_touch\nbefore update on post for each row\nbegin\n  :new.updated_at := systimestamp;\nend;",
+		"INSERT INTO post (id) VALUES (1)",
+	}, up)
+	down, _, err := ParseSQLMigration(strings.NewReader(migration), DirectionDown, debug, WithPLSQL())
+	require.NoError(t, err)
+	require.Equal(t, []string{"DROP TRIGGER post_touch", "DROP PROCEDURE touch_post", "DROP TABLE post"}, down)
+
+	// Without the option, semicolons end statements and slashes are part of them.
+	s := "-- +goose Up\nBEGIN\n  touch_post(1);\nEND;\n/\nSELECT 1 FROM dual;\n"
+	up, _, err = ParseSQLMigration(strings.NewReader(s), DirectionUp, debug)
+	require.NoError(t, err)
+	require.Equal(t, []string{"BEGIN\n  touch_post(1);", "END;", "/\nSELECT 1 FROM dual;"}, up)
+
+	t.Run("unterminated_block", func(t *testing.T) {
+		s := "-- +goose Up\nDECLARE\n  n NUMBER;\nBEGIN\n  n := 1;\nEND;\n-- +goose Down\n"
+		_, _, err := ParseSQLMigration(strings.NewReader(s), DirectionUp, debug, WithPLSQL())
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, 2, parseErr.Line)
+		require.Contains(t, err.Error(), `missing delimiter "/"?`)
+	})
+	t.Run("multiline_header", func(t *testing.T) {
+		s := "-- +goose Up\nCREATE OR REPLACE\n  PROCEDURE touch_post AS\nBEGIN\n  NULL;\nEND;\n/\n" +
+			"CREATE OR REPLACE\n  VIEW v AS SELECT 1 FROM dual;\n"
+		stmts, _, err := ParseSQLMigration(strings.NewReader(s), DirectionUp, debug, WithPLSQL())
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"CREATE OR REPLACE\n  PROCEDURE touch_post AS\nBEGIN\n  NULL;\nEND;",
+			"CREATE OR REPLACE\n  VIEW v AS SELECT 1 FROM dual",
+		}, stmts)
+	})
+	t.Run("custom_delimiter", func(t *testing.T) {
+		s := "-- +goose Up\n-- +goose DELIMITER $$\nBEGIN\n  NULL;\nEND;$$\n"
+		stmts, _, err := ParseSQLMigration(strings.NewReader(s), DirectionUp, debug, WithPLSQL())
+		require.NoError(t, err)
+		require.Equal(t, []string{"BEGIN\n  NULL;\nEND;"}, stmts)
+	})
+}
+
+func TestIsPLSQLBlock(t *testing.T) {
+	t.Parallel()
+
+	type result struct{ block, decided bool }
+	for line, want := range map[string]result{
+		"BEGIN":   {true, true},
+		"declare": {true, true},
+		"CREATE OR REPLACE PACKAGE BODY billing AS":      {true, true},
+		"CREATE EDITIONABLE FUNCTION f RETURN NUMBER":    {true, true},
+		"create or replace noneditionable trigger t":     {true, true},
+		"CREATE TYPE point AS OBJECT (x NUMBER)":         {true, true},
+		"CREATE TABLE post (id NUMBER)":                  {false, true},
+		"CREATE OR REPLACE VIEW v AS SELECT 1 FROM dual": {false, true},
+		"BEGINNING_BALANCE := 1;":                        {false, true},
+		// The type of the object may be on the next line.
+		"CREATE OR REPLACE -- the procedure": {false, false},
+		"CREATE OR":                          {false, false},
+		"create editionable":                 {false, false},
+		"":                                   {false, false},
+	} {
+		block, decided := isPLSQLBlock(plsqlWords(line))
+		require.Equal(t, want, result{block, decided}, line)
+	}
+}
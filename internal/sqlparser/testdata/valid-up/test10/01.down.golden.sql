@@ -0,0 +1 @@
+DROP FUNCTION double_it
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE TABLE accounts (id int, balance int);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP PROCEDURE deposit
\ No newline at end of file
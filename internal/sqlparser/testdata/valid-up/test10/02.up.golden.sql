@@ -0,0 +1,5 @@
+CREATE PROCEDURE deposit(IN account_id int, IN amount int)
+BEGIN
+  UPDATE accounts SET balance = balance + amount WHERE id = account_id;
+  SELECT balance FROM accounts WHERE id = account_id;
+END
\ No newline at end of file
@@ -0,0 +1,4 @@
+CREATE FUNCTION double_it(x int) RETURNS int DETERMINISTIC
+BEGIN
+  RETURN x * 2;
+END -- trailing comment
\ No newline at end of file
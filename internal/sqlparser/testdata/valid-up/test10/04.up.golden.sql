@@ -0,0 +1 @@
+INSERT INTO accounts (id, balance) VALUES (1, 0);
\ No newline at end of file
@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE accounts (id int, balance int);
+
+-- +goose DELIMITER //
+CREATE PROCEDURE deposit(IN account_id int, IN amount int)
+BEGIN
+  UPDATE accounts SET balance = balance + amount WHERE id = account_id;
+  SELECT balance FROM accounts WHERE id = account_id;
+END //
+
+CREATE FUNCTION double_it(x int) RETURNS int DETERMINISTIC
+BEGIN
+  RETURN x * 2;
+END// -- trailing comment
+-- +goose DELIMITER ;
+
+INSERT INTO accounts (id, balance) VALUES (1, 0);
+
+-- +goose Down
+-- +goose DELIMITER $$
+DROP FUNCTION double_it $$
+DROP PROCEDURE deposit $$
+-- +goose DELIMITER ;
+DROP TABLE accounts;
@@ -1,9 +1,14 @@
 package integration
 
 import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	_ "github.com/marcboeker/go-duckdb"
 	"github.com/pressly/goose/v3"
 	"github.com/pressly/goose/v3/database"
 	"github.com/pressly/goose/v3/internal/testing/testdb"
@@ -21,6 +26,17 @@ func TestPostgres(t *testing.T) {
 	testDatabase(t, database.DialectPostgres, db, "testdata/migrations/postgres")
 }
 
+func TestCockroachDB(t *testing.T) {
+	t.Parallel()
+
+	db, cleanup, err := testdb.NewCockroachDB()
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+	require.NoError(t, db.Ping())
+
+	testDatabase(t, database.DialectCockroachDB, db, "testdata/migrations/cockroachdb")
+}
+
 func TestSpanner(t *testing.T) {
 	t.Parallel()
 
@@ -145,3 +161,59 @@ func TestStarrocks(t *testing.T) {
 
 	testDatabase(t, database.DialectStarrocks, db, "testdata/migrations/starrocks", goose.WithIsolateDDL(true))
 }
+
+func TestOracle(t *testing.T) {
+	t.Parallel()
+
+	db, cleanup, err := testdb.NewOracle()
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+	require.NoError(t, db.Ping())
+
+	testDatabase(t, database.DialectOracle, db, "testdata/migrations/oracle")
+
+	// The procedure created by the PL/SQL migration inserted a repository.
+	var count int
+	err = db.QueryRow(`SELECT COUNT(*) FROM repos WHERE repo_full_name = 'pressly/sup'`).Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func TestDuckDB(t *testing.T) {
+	t.Parallel()
+
+	// DuckDB runs in-process, a database is a file like SQLite.
+	path := filepath.Join(t.TempDir(), "warehouse.duckdb")
+	db, err := sql.Open("duckdb", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.Ping())
+
+	testDatabase(t, database.DialectDuckDB, db, "testdata/migrations/duckdb")
+
+	rows, err := db.Query(`SELECT repo_full_name FROM repos_of('pressly')`)
+	require.NoError(t, err)
+	var repos []string
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		repos = append(repos, name)
+	}
+	require.NoError(t, rows.Err())
+	require.NoError(t, rows.Close())
+	require.Equal(t, []string{"pressly/goose"}, repos)
+
+	// The version table and its sequence persist in the database file.
+	require.NoError(t, db.Close())
+	db, err = sql.Open("duckdb", path)
+	require.NoError(t, err)
+	p, err := goose.NewProvider(database.DialectDuckDB, db, os.DirFS("testdata/migrations/duckdb"))
+	require.NoError(t, err)
+	t.Cleanup(func() { p.Close() })
+	version, err := p.GetDBVersion(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 6, version)
+	hasPending, err := p.HasPending(context.Background())
+	require.NoError(t, err)
+	require.False(t, hasPending)
+}
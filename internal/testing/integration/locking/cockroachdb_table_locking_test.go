@@ -0,0 +1,65 @@
+package locking_test
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/internal/testing/testdb"
+	"github.com/pressly/goose/v3/lock"
+	"github.com/pressly/goose/v3/lock/locktesting"
+	"github.com/pressly/goose/v3/testdata"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCockroachDBTableLocking(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+
+	db, cleanup, err := testdb.NewCockroachDB()
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	t.Run("concurrent", func(t *testing.T) {
+		// All lockers must compete for the SAME lock ID
+		lockID := rand.Int64()
+
+		newLocker := func(t *testing.T) lock.Locker {
+			locker, err := lock.NewCockroachDBTableLocker(
+				lock.WithTableLockID(lockID),
+				lock.WithTableHeartbeatInterval(200*time.Millisecond),
+				lock.WithTableLockTimeout(50*time.Millisecond, 2), // 200ms total wait time
+			)
+			require.NoError(t, err)
+			return locker
+		}
+
+		locktesting.TestConcurrentLocking(t, db, newLocker, 1*time.Second)
+	})
+	t.Run("provider", func(t *testing.T) {
+		// Use the same lock ID for all providers so they compete for the same table row
+		sharedLockID := rand.Int64()
+
+		locktesting.TestProviderLocking(t, func(t *testing.T) *goose.Provider {
+			t.Helper()
+
+			locker, err := lock.NewCockroachDBTableLocker(
+				lock.WithTableLockID(sharedLockID),
+				lock.WithTableLockTimeout(200*time.Millisecond, 25), // 25 retries, 5s total
+			)
+			require.NoError(t, err)
+
+			p, err := goose.NewProvider(
+				goose.DialectCockroachDB,
+				db,
+				testdata.MustMigrationsFS(),
+				goose.WithLocker(locker),
+			)
+			require.NoError(t, err)
+			return p
+		})
+	})
+}
@@ -0,0 +1,26 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/pressly/goose/v3/database"
+	"github.com/pressly/goose/v3/database/storetest"
+	"github.com/pressly/goose/v3/internal/testing/testdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresStore(t *testing.T) {
+	t.Parallel()
+
+	db, cleanup, err := testdb.NewPostgres()
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+	require.NoError(t, db.Ping())
+
+	storetest.TestStore(t, db, func(t *testing.T, tableName string) database.Store {
+		t.Helper()
+		store, err := database.NewStore(database.DialectPostgres, tableName)
+		require.NoError(t, err)
+		return store
+	})
+}
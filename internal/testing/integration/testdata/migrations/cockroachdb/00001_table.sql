@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE owners (
+    owner_id INT8 PRIMARY KEY,
+    owner_name STRING NOT NULL,
+    owner_type STRING NOT NULL CHECK (owner_type IN ('user', 'organization'))
+);
+
+CREATE TABLE IF NOT EXISTS repos (
+    repo_id INT8 NOT NULL DEFAULT unique_rowid(),
+    repo_full_name STRING NOT NULL,
+    repo_owner_id INT8 NOT NULL REFERENCES owners(owner_id) ON DELETE CASCADE,
+
+    PRIMARY KEY (repo_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS repos;
+DROP TABLE IF EXISTS owners;
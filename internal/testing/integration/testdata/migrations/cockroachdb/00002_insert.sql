@@ -0,0 +1,12 @@
+-- +goose Up
+INSERT INTO owners(owner_id, owner_name, owner_type)
+    VALUES (1, 'lucas', 'user'), (2, 'space', 'organization');
+
+INSERT INTO owners(owner_id, owner_name, owner_type)
+    VALUES (3, 'james', 'user'), (4, 'pressly', 'organization');
+
+INSERT INTO repos(repo_full_name, repo_owner_id)
+    VALUES ('james/rover', 3), ('pressly/goose', 4);
+
+-- +goose Down
+DELETE FROM owners;
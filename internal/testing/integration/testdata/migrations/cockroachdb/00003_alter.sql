@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE repos ADD COLUMN IF NOT EXISTS homepage_url STRING;
+ALTER TABLE repos ADD COLUMN is_private BOOL NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE repos DROP COLUMN IF EXISTS homepage_url;
+ALTER TABLE repos DROP COLUMN is_private;
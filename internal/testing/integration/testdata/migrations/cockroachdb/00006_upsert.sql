@@ -0,0 +1,6 @@
+-- +goose Up
+UPSERT INTO owners(owner_id, owner_name, owner_type) VALUES (4, 'pressly', 'organization');
+INSERT INTO repos(repo_full_name, repo_owner_id) VALUES ('pressly/sup', 4);
+
+-- +goose Down
+DELETE FROM repos WHERE repo_full_name = 'pressly/sup';
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE owner_type AS ENUM('user', 'organization');
+
+CREATE SEQUENCE owners_owner_id_seq START 1;
+
+CREATE TABLE owners (
+    owner_id BIGINT PRIMARY KEY DEFAULT nextval('owners_owner_id_seq'),
+    owner_name TEXT NOT NULL,
+    owner_type owner_type NOT NULL
+);
+
+CREATE SEQUENCE repos_repo_id_seq START 1;
+
+CREATE TABLE IF NOT EXISTS repos (
+    repo_id BIGINT NOT NULL DEFAULT nextval('repos_repo_id_seq'),
+    repo_full_name TEXT NOT NULL,
+    -- No foreign key: DuckDB supports neither ON DELETE CASCADE nor deleting the referenced rows
+    -- in the same transaction as the referencing ones.
+    repo_owner_id BIGINT NOT NULL,
+
+    PRIMARY KEY (repo_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS repos;
+DROP SEQUENCE IF EXISTS repos_repo_id_seq;
+DROP TABLE IF EXISTS owners;
+DROP SEQUENCE IF EXISTS owners_owner_id_seq;
+DROP TYPE owner_type;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE repos ADD COLUMN IF NOT EXISTS homepage_url TEXT;
+ALTER TABLE repos ADD COLUMN is_private BOOLEAN DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE repos DROP COLUMN IF EXISTS homepage_url;
+ALTER TABLE repos DROP COLUMN is_private;
+-- +goose StatementEnd
@@ -0,0 +1 @@
+-- +goose Up
@@ -0,0 +1,12 @@
+-- +goose up
+-- +goose statementbegin
+CREATE OR REPLACE MACRO repos_of(p_owner_name) AS TABLE
+    SELECT r.repo_id, r.repo_full_name
+    FROM repos r
+    JOIN owners o ON o.owner_id = r.repo_owner_id
+    WHERE o.owner_name = p_owner_name
+    ORDER BY r.repo_id;
+-- +goose statementend
+
+-- +goose down
+DROP MACRO TABLE IF EXISTS repos_of;
@@ -0,0 +1,13 @@
+-- +goose Up
+INSERT INTO owners(owner_name, owner_type) VALUES ('lucas', 'user');
+INSERT INTO owners(owner_name, owner_type) VALUES ('space', 'organization');
+INSERT INTO owners(owner_name, owner_type) VALUES ('james', 'user');
+INSERT INTO owners(owner_name, owner_type) VALUES ('pressly', 'organization');
+
+INSERT INTO repos(repo_full_name, repo_owner_id)
+    SELECT 'james/rover', owner_id FROM owners WHERE owner_name = 'james';
+INSERT INTO repos(repo_full_name, repo_owner_id)
+    SELECT 'pressly/goose', owner_id FROM owners WHERE owner_name = 'pressly';
+
+-- +goose Down
+DELETE FROM owners;
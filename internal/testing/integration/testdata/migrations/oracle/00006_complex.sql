@@ -0,0 +1,38 @@
+-- +goose Up
+-- PL/SQL blocks contain semicolons and are terminated by a slash on its own line. The header may
+-- span several lines.
+CREATE OR REPLACE
+PROCEDURE insert_repository(
+    p_repo_full_name IN VARCHAR2,
+    p_owner_name IN VARCHAR2,
+    p_owner_type IN VARCHAR2
+) AS
+    v_owner_id owners.owner_id%TYPE;
+BEGIN
+    -- Check if the owner already exists
+    BEGIN
+        SELECT owner_id INTO v_owner_id
+        FROM owners
+        WHERE owner_name = p_owner_name AND owner_type = p_owner_type;
+    EXCEPTION
+        WHEN NO_DATA_FOUND THEN
+            -- If the owner does not exist, insert a new owner
+            INSERT INTO owners (owner_name, owner_type)
+            VALUES (p_owner_name, p_owner_type)
+            RETURNING owner_id INTO v_owner_id;
+    END;
+
+    -- Insert the repository using the obtained owner_id
+    INSERT INTO repos (repo_full_name, repo_owner_id)
+    VALUES (p_repo_full_name, v_owner_id);
+END;
+/
+
+BEGIN
+    insert_repository('pressly/sup', 'pressly', 'organization');
+END;
+/
+
+-- +goose Down
+DELETE FROM repos WHERE repo_full_name = 'pressly/sup';
+DROP PROCEDURE insert_repository;
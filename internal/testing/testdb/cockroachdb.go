@@ -0,0 +1,87 @@
+package testdb
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+)
+
+const (
+	// https://hub.docker.com/r/cockroachdb/cockroach
+	COCKROACHDB_IMAGE   = "cockroachdb/cockroach"
+	COCKROACHDB_VERSION = "latest-v24.3"
+
+	COCKROACHDB_DB   = "defaultdb"
+	COCKROACHDB_USER = "root"
+)
+
+func newCockroachDB(opts ...OptionsFunc) (*sql.DB, func(), error) {
+	option := &options{}
+	for _, f := range opts {
+		f(option)
+	}
+	// Uses a sensible default on windows (tcp/http) and linux/osx (socket).
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to docker: %v", err)
+	}
+	options := &dockertest.RunOptions{
+		Repository:   COCKROACHDB_IMAGE,
+		Tag:          COCKROACHDB_VERSION,
+		Cmd:          []string{"start-single-node", "--insecure"},
+		Labels:       map[string]string{"goose_test": "1"},
+		PortBindings: make(map[docker.Port][]docker.PortBinding),
+	}
+	if option.bindPort > 0 {
+		options.PortBindings[docker.Port("26257/tcp")] = []docker.PortBinding{
+			{HostPort: strconv.Itoa(option.bindPort)},
+		}
+	}
+	container, err := pool.RunWithOptions(
+		options,
+		func(config *docker.HostConfig) {
+			// Set AutoRemove to true so that stopped container goes away by itself.
+			config.AutoRemove = true
+			config.RestartPolicy = docker.RestartPolicy{Name: "no"}
+		},
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create docker container: %v", err)
+	}
+	cleanup := func() {
+		if option.debug {
+			// User must manually delete the Docker container.
+			return
+		}
+		if err := pool.Purge(container); err != nil {
+			log.Printf("failed to purge resource: %v", err)
+		}
+	}
+	dsn := fmt.Sprintf("postgres://%s@%s:%s/%s?sslmode=disable",
+		COCKROACHDB_USER,
+		"localhost",
+		container.GetPort("26257/tcp"), // Fetch port dynamically assigned to container
+		COCKROACHDB_DB,
+	)
+	var db *sql.DB
+	// Exponential backoff-retry, because the application in the container
+	// might not be ready to accept connections yet.
+	if err := pool.Retry(
+		func() error {
+			var err error
+			db, err = sql.Open("pgx", dsn)
+			if err != nil {
+				return err
+			}
+			return db.Ping()
+		},
+	); err != nil {
+		return nil, cleanup, fmt.Errorf("could not connect to docker database: %v", err)
+	}
+	return db, cleanup, nil
+}
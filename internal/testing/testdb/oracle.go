@@ -0,0 +1,96 @@
+package testdb
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	_ "github.com/sijms/go-ora/v2"
+)
+
+const (
+	// https://hub.docker.com/r/gvenzl/oracle-free
+	ORACLE_IMAGE   = "gvenzl/oracle-free"
+	ORACLE_VERSION = "23-slim-faststart"
+
+	ORACLE_SERVICE  = "FREEPDB1"
+	ORACLE_USER     = "tester"
+	ORACLE_PASSWORD = "password1"
+)
+
+func newOracle(opts ...OptionsFunc) (*sql.DB, func(), error) {
+	option := &options{}
+	for _, f := range opts {
+		f(option)
+	}
+	// Uses a sensible default on windows (tcp/http) and linux/osx (socket).
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to docker: %v", err)
+	}
+	// Oracle takes a while to open the pluggable database, even with the faststart image.
+	pool.MaxWait = 5 * time.Minute
+	options := &dockertest.RunOptions{
+		Repository: ORACLE_IMAGE,
+		Tag:        ORACLE_VERSION,
+		Env: []string{
+			"ORACLE_PASSWORD=" + ORACLE_PASSWORD,
+			"APP_USER=" + ORACLE_USER,
+			"APP_USER_PASSWORD=" + ORACLE_PASSWORD,
+		},
+		Labels:       map[string]string{"goose_test": "1"},
+		PortBindings: make(map[docker.Port][]docker.PortBinding),
+	}
+	if option.bindPort > 0 {
+		options.PortBindings[docker.Port("1521/tcp")] = []docker.PortBinding{
+			{HostPort: strconv.Itoa(option.bindPort)},
+		}
+	}
+	container, err := pool.RunWithOptions(
+		options,
+		func(config *docker.HostConfig) {
+			// Set AutoRemove to true so that stopped container goes away by itself.
+			config.AutoRemove = true
+			config.RestartPolicy = docker.RestartPolicy{Name: "no"}
+		},
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create docker container: %v", err)
+	}
+	cleanup := func() {
+		if option.debug {
+			// User must manually delete the Docker container.
+			return
+		}
+		if err := pool.Purge(container); err != nil {
+			log.Printf("failed to purge resource: %v", err)
+		}
+	}
+	dsn := fmt.Sprintf("oracle://%s:%s@%s:%s/%s",
+		ORACLE_USER,
+		ORACLE_PASSWORD,
+		"localhost",
+		container.GetPort("1521/tcp"), // Fetch port dynamically assigned to container
+		ORACLE_SERVICE,
+	)
+	var db *sql.DB
+	// Exponential backoff-retry, because the application in the container
+	// might not be ready to accept connections yet.
+	if err := pool.Retry(
+		func() error {
+			var err error
+			db, err = sql.Open("oracle", dsn)
+			if err != nil {
+				return err
+			}
+			return db.Ping()
+		},
+	); err != nil {
+		return nil, cleanup, fmt.Errorf("could not connect to docker database: %v", err)
+	}
+	return db, cleanup, nil
+}
@@ -7,6 +7,12 @@ func NewClickHouse(options ...OptionsFunc) (db *sql.DB, cleanup func(), err erro
 	return newClickHouse(options...)
 }
 
+// NewCockroachDB starts a CockroachDB docker container. Returns db connection and a docker cleanup
+// function.
+func NewCockroachDB(options ...OptionsFunc) (db *sql.DB, cleanup func(), err error) {
+	return newCockroachDB(options...)
+}
+
 // NewPostgres starts a PostgreSQL docker container. Returns db connection and a docker cleanup function.
 func NewPostgres(options ...OptionsFunc) (db *sql.DB, cleanup func(), err error) {
 	return newPostgres(options...)
@@ -31,3 +37,9 @@ func NewYdb(options ...OptionsFunc) (db *sql.DB, cleanup func(), err error) {
 func NewStarrocks(options ...OptionsFunc) (db *sql.DB, cleanup func(), err error) {
 	return newStarrocks(options...)
 }
+
+// NewOracle starts an Oracle Database Free docker container. Returns db connection and a docker
+// cleanup function.
+func NewOracle(options ...OptionsFunc) (db *sql.DB, cleanup func(), err error) {
+	return newOracle(options...)
+}
@@ -0,0 +1,293 @@
+// Package lint runs static checks over goose migration files.
+//
+// [Lint] reads every versioned .sql and .go migration in a filesystem, parses it with the
+// [sqlparser] package and reports [Finding]s for a set of built-in rules, such as a missing down
+// section or a DROP TABLE statement. See [Rules] for the full list.
+//
+// Rules can be disabled globally with [WithDisabledRules], or per file with an annotation listing
+// the rule IDs to ignore:
+//
+//	-- +goose LINT IGNORE drop-table,drop-column
+//
+// Go migrations use the same annotation in a line comment: // +goose LINT IGNORE missing-down.
+//
+// Findings can be written as text, JSON or SARIF, see [WriteText], [WriteJSON] and [WriteSARIF].
+package lint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
+	"github.com/pressly/goose/v3/internal/migrationstats"
+	"github.com/pressly/goose/v3/internal/sqlparser"
+)
+
+// Severity is the severity of a [Finding].
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Finding is a single problem reported by a rule.
+type Finding struct {
+	// Rule is the ID of the rule that produced the finding, e.g., "drop-table".
+	Rule string `json:"rule"`
+	// Severity is the severity of the rule.
+	Severity Severity `json:"severity"`
+	// Path is the migration file path, relative to the linted filesystem.
+	Path string `json:"path"`
+	// Line and Column are 1-based. Column is 0 if unknown.
+	Line   int `json:"line"`
+	Column int `json:"column,omitempty"`
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+func (f *Finding) String() string {
+	loc := fmt.Sprintf("%s:%d", f.Path, f.Line)
+	if f.Column > 0 {
+		loc += fmt.Sprintf(":%d", f.Column)
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", loc, f.Severity, f.Message, f.Rule)
+}
+
+// HasErrors reports whether any of the findings has [SeverityError].
+func HasErrors(findings []*Finding) bool {
+	return slices.ContainsFunc(findings, func(f *Finding) bool {
+		return f.Severity == SeverityError
+	})
+}
+
+// Option configures [Lint].
+type Option interface {
+	apply(*config) error
+}
+
+// WithDialect enables dialect-specific rules, such as [RuleCreateIndexNotConcurrently] for
+// Postgres. By default, only dialect-agnostic rules run.
+func WithDialect(dialect database.Dialect) Option {
+	return optionFunc(func(c *config) error {
+		c.dialect = dialect
+		return nil
+	})
+}
+
+// WithDisabledRules disables the given rules for all files. If called multiple times, the list is
+// merged. Unknown rule IDs are reported as an error.
+func WithDisabledRules(ids ...string) Option {
+	return optionFunc(func(c *config) error {
+		for _, id := range ids {
+			if _, ok := lookupRule(id); !ok {
+				return fmt.Errorf("unknown rule: %q", id)
+			}
+			c.disabled[id] = true
+		}
+		return nil
+	})
+}
+
+// WithLargeTables limits [RuleColumnTypeChange] to the given tables. Table names are matched
+// case-insensitively and may be schema-qualified. By default, type changes on any table are
+// reported, because the linter cannot know how large a table is.
+func WithLargeTables(tables ...string) Option {
+	return optionFunc(func(c *config) error {
+		for _, t := range tables {
+			if t == "" {
+				return errors.New("large table name must not be empty")
+			}
+			c.largeTables[strings.ToLower(t)] = true
+		}
+		return nil
+	})
+}
+
+type config struct {
+	dialect     database.Dialect
+	disabled    map[string]bool
+	largeTables map[string]bool
+}
+
+type optionFunc func(*config) error
+
+func (f optionFunc) apply(cfg *config) error {
+	return f(cfg)
+}
+
+// Lint checks all versioned migration files in the root of fsys and returns the findings, ordered
+// by path and line. Files that do not have a numeric version prefix are ignored, like goose does
+// when collecting migrations.
+//
+// A SQL file that fails to parse is reported as a [RuleParseError] finding rather than an error;
+// the returned error is reserved for problems reading fsys.
+func Lint(fsys fs.FS, opts ...Option) ([]*Finding, error) {
+	cfg := &config{
+		disabled:    make(map[string]bool),
+		largeTables: make(map[string]bool),
+	}
+	for _, opt := range opts {
+		if err := opt.apply(cfg); err != nil {
+			return nil, err
+		}
+	}
+	var filenames []string
+	for _, pattern := range []string{"*.sql", "*.go"} {
+		files, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to glob pattern %q: %w", pattern, err)
+		}
+		filenames = append(filenames, files...)
+	}
+	slices.Sort(filenames)
+
+	var findings []*Finding
+	for _, filename := range filenames {
+		if strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+		if _, err := goose.NumericComponent(filename); err != nil {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, filename)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, lintFile(cfg, filename, data)...)
+	}
+	return findings, nil
+}
+
+// file is a migration file being linted.
+type file struct {
+	path string
+	// lines are the raw source lines, used to compute columns and find annotations.
+	lines []string
+	// stats is nil if the file could not be analyzed by migrationstats, e.g., a Go migration
+	// registered with the Provider API rather than an init function.
+	stats *migrationstats.Stats
+	// parsed is nil for Go migrations.
+	parsed *sqlparser.File
+	// ignored are the rules suppressed with a LINT IGNORE annotation.
+	ignored map[string]bool
+}
+
+func lintFile(cfg *config, filename string, data []byte) []*Finding {
+	f := &file{
+		path:    filename,
+		lines:   splitLines(data),
+		ignored: make(map[string]bool),
+	}
+	isSQL := path.Ext(filename) == ".sql"
+	for _, line := range f.lines {
+		if ids, ok := lintIgnore(line, isSQL); ok {
+			for _, id := range ids {
+				f.ignored[id] = true
+			}
+		}
+	}
+	if isSQL {
+		var opts []sqlparser.ParseOption
+		if cfg.dialect == database.DialectOracle {
+			opts = append(opts, sqlparser.WithPLSQL())
+		}
+		parsed, err := sqlparser.Parse(bytes.NewReader(data), opts...)
+		if err != nil {
+			if cfg.disabled[RuleParseError] || f.ignored[RuleParseError] {
+				return nil
+			}
+			finding := &Finding{
+				Rule:     RuleParseError,
+				Severity: SeverityError,
+				Path:     filename,
+				Line:     1,
+				Message:  err.Error(),
+			}
+			var parseErr *sqlparser.ParseError
+			if errors.As(err, &parseErr) {
+				finding.Line = max(parseErr.Line, 1)
+				finding.Column = parseErr.Column
+				finding.Message = parseErr.Err.Error()
+			}
+			return []*Finding{finding}
+		}
+		f.parsed = parsed
+	}
+	stats, err := migrationstats.GatherStats(singleFileWalker{filename: filename, data: data}, false)
+	if err == nil && len(stats) == 1 {
+		f.stats = stats[0]
+	}
+
+	var findings []*Finding
+	for _, rule := range rules {
+		if cfg.disabled[rule.ID] || f.ignored[rule.ID] {
+			continue
+		}
+		if rule.sqlOnly && !isSQL {
+			continue
+		}
+		if len(rule.Dialects) > 0 && !slices.Contains(rule.Dialects, cfg.dialect) {
+			continue
+		}
+		for _, finding := range rule.check(cfg, f) {
+			finding.Rule = rule.ID
+			finding.Severity = rule.Severity
+			finding.Path = filename
+			findings = append(findings, finding)
+		}
+	}
+	slices.SortStableFunc(findings, func(a, b *Finding) int {
+		return a.Line - b.Line
+	})
+	return findings
+}
+
+// lintIgnore returns the rule IDs listed in a "+goose LINT IGNORE" annotation. SQL files use a "--"
+// comment, Go files a "//" comment.
+func lintIgnore(line string, isSQL bool) ([]string, bool) {
+	if isSQL {
+		if !strings.HasPrefix(line, "--") || !strings.Contains(line, "+goose") {
+			return nil, false
+		}
+		if a, err := sqlparser.ExtractAnnotation(line); err != nil || a != sqlparser.AnnotationLintIgnore {
+			return nil, false
+		}
+	} else if !strings.HasPrefix(strings.TrimSpace(line), "//") {
+		return nil, false
+	}
+	_, after, ok := strings.Cut(line, "+goose")
+	if !ok {
+		return nil, false
+	}
+	fields := strings.Fields(strings.ReplaceAll(after, ",", " "))
+	if len(fields) < 2 || !strings.EqualFold(fields[0]+" "+fields[1], string(sqlparser.AnnotationLintIgnore)) {
+		return nil, false
+	}
+	return fields[2:], true
+}
+
+func splitLines(data []byte) []string {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+// singleFileWalker is a [migrationstats.FileWalker] for a single in-memory file.
+type singleFileWalker struct {
+	filename string
+	data     []byte
+}
+
+func (w singleFileWalker) Walk(fn func(filename string, r io.Reader) error) error {
+	return fn(w.filename, bytes.NewReader(w.data))
+}
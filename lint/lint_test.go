@@ -0,0 +1,230 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pressly/goose/v3/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("clean", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"00001_users.sql": sqlFile(`
+-- +goose Up
+CREATE TABLE users (id int);
+CREATE INDEX users_id_idx ON users (id);
+ALTER TABLE users ADD COLUMN name text NOT NULL;
+-- +goose Down
+DROP TABLE users;
+`),
+			"README.md": {Data: []byte("not a migration")},
+		}
+		findings, err := Lint(fsys, WithDialect(database.DialectPostgres))
+		require.NoError(t, err)
+		require.Empty(t, findings)
+	})
+	t.Run("all_rules", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"00001_a.sql": sqlFile(`
+-- +goose Up
+DROP TABLE legacy;
+ALTER TABLE users DROP COLUMN age;
+CREATE INDEX users_name_idx ON users (name);
+CREATE INDEX CONCURRENTLY users_email_idx ON users (email);
+ALTER TABLE users ALTER COLUMN id TYPE bigint;
+ALTER TABLE users ADD COLUMN email text NOT NULL, ADD COLUMN status text NOT NULL DEFAULT 'new';
+-- +goose Down
+`),
+		}
+		findings, err := Lint(fsys, WithDialect(database.DialectPostgres))
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"00001_a.sql:2:1: error: DROP TABLE in up migration destroys data (drop-table)",
+			"00001_a.sql:3:19: error: DROP COLUMN in up migration destroys data (drop-column)",
+			"00001_a.sql:4:1: warning: CREATE INDEX on users without CONCURRENTLY blocks writes while the index is built (create-index-not-concurrently)",
+			"00001_a.sql:5:1: error: CONCURRENTLY cannot run inside a transaction, add -- +goose NO TRANSACTION to the file (concurrently-in-transaction)",
+			"00001_a.sql:6:19: warning: column type change on users may rewrite the table and hold a lock for its duration (column-type-change)",
+			"00001_a.sql:7:19: error: NOT NULL column added to users without a DEFAULT fails if the table has rows (not-null-without-default)",
+			"00001_a.sql:8: warning: migration has no down statements, rolling it back will only remove the version (missing-down)",
+		}, findingStrings(findings))
+		require.True(t, HasErrors(findings))
+	})
+	t.Run("dialect_specific_rule", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"00001_a.sql": sqlFile(`
+-- +goose Up
+CREATE INDEX users_name_idx ON users (name);
+-- +goose Down
+DROP INDEX users_name_idx;
+`),
+		}
+		findings, err := Lint(fsys, WithDialect(database.DialectMySQL))
+		require.NoError(t, err)
+		require.Empty(t, findings)
+	})
+	t.Run("no_transaction", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"00001_a.sql": sqlFile(`
+-- +goose NO TRANSACTION
+-- +goose Up
+CREATE INDEX CONCURRENTLY users_name_idx ON users (name);
+-- +goose Down
+DROP INDEX CONCURRENTLY users_name_idx;
+`),
+		}
+		findings, err := Lint(fsys, WithDialect(database.DialectPostgres))
+		require.NoError(t, err)
+		require.Empty(t, findings)
+	})
+	t.Run("large_tables", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"00001_a.sql": sqlFile(`
+-- +goose Up
+ALTER TABLE small ALTER COLUMN id TYPE bigint;
+ALTER TABLE public.events ALTER COLUMN id TYPE bigint;
+-- +goose Down
+SELECT 1;
+`),
+		}
+		findings, err := Lint(fsys, WithLargeTables("events"))
+		require.NoError(t, err)
+		require.Len(t, findings, 1)
+		require.Equal(t, RuleColumnTypeChange, findings[0].Rule)
+		require.Equal(t, 3, findings[0].Line)
+	})
+	t.Run("disabled_and_ignored", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"00001_a.sql": sqlFile(`
+-- +goose LINT IGNORE drop-table
+-- +goose Up
+DROP TABLE legacy;
+ALTER TABLE users DROP COLUMN age;
+-- +goose Down
+`),
+			"00002_b.go": {Data: []byte(`package migrations
+
+// +goose LINT IGNORE missing-down
+func init() {
+	goose.AddMigrationContext(up, nil)
+}
+`)},
+		}
+		findings, err := Lint(fsys, WithDisabledRules(RuleMissingDown))
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"00001_a.sql:4:19: error: DROP COLUMN in up migration destroys data (drop-column)",
+		}, findingStrings(findings))
+
+		_, err = Lint(fsys, WithDisabledRules("no-such-rule"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `unknown rule: "no-such-rule"`)
+	})
+	t.Run("go_missing_down", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"00002_b.go": {Data: []byte(`package migrations
+
+func init() {
+	goose.AddMigrationContext(up, nil)
+}
+`)},
+		}
+		findings, err := Lint(fsys)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"00002_b.go:1: warning: migration has no down statements, rolling it back will only remove the version (missing-down)",
+		}, findingStrings(findings))
+	})
+	t.Run("parse_error", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"00001_a.sql": sqlFile(`
+-- +goose Up
+SELECT 1
+-- +goose Down
+SELECT 2;
+`),
+		}
+		findings, err := Lint(fsys)
+		require.NoError(t, err)
+		require.Len(t, findings, 1)
+		require.Equal(t, RuleParseError, findings[0].Rule)
+		require.Equal(t, SeverityError, findings[0].Severity)
+		require.Equal(t, "00001_a.sql", findings[0].Path)
+		require.Positive(t, findings[0].Line)
+	})
+}
+
+func TestRules(t *testing.T) {
+	t.Parallel()
+
+	seen := make(map[string]bool)
+	for _, r := range Rules() {
+		require.NotEmpty(t, r.ID)
+		require.NotEmpty(t, r.Description)
+		require.False(t, seen[r.ID], "duplicate rule %q", r.ID)
+		seen[r.ID] = true
+	}
+	require.True(t, seen[RuleParseError])
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	findings := []*Finding{{
+		Rule:     RuleDropTable,
+		Severity: SeverityError,
+		Path:     "00001_a.sql",
+		Line:     3,
+		Column:   1,
+		Message:  "DROP TABLE in up migration destroys data",
+	}}
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteText(&buf, findings))
+		require.Equal(t, "00001_a.sql:3:1: error: DROP TABLE in up migration destroys data (drop-table)\n", buf.String())
+	})
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteJSON(&buf, findings))
+		var got []*Finding
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		require.Equal(t, findings, got)
+
+		buf.Reset()
+		require.NoError(t, WriteJSON(&buf, nil))
+		require.Equal(t, "[]\n", buf.String())
+	})
+	t.Run("sarif", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteSARIF(&buf, findings))
+		var got sarifLog
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		require.Equal(t, "2.1.0", got.Version)
+		require.Len(t, got.Runs, 1)
+		require.Equal(t, "goose", got.Runs[0].Tool.Driver.Name)
+		require.Len(t, got.Runs[0].Tool.Driver.Rules, len(Rules()))
+		require.Len(t, got.Runs[0].Results, 1)
+		result := got.Runs[0].Results[0]
+		require.Equal(t, RuleDropTable, result.RuleID)
+		require.Equal(t, "error", result.Level)
+		require.Equal(t, "00001_a.sql", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		require.Equal(t, 3, result.Locations[0].PhysicalLocation.Region.StartLine)
+	})
+}
+
+func sqlFile(s string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(s[1:])}
+}
+
+func findingStrings(findings []*Finding) []string {
+	var out []string
+	for _, f := range findings {
+		out = append(out, f.String())
+	}
+	return out
+}
@@ -0,0 +1,368 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pressly/goose/v3/database"
+	"github.com/pressly/goose/v3/internal/sqlparser"
+)
+
+// Rule IDs.
+const (
+	// RuleParseError reports SQL migrations that goose cannot parse.
+	RuleParseError = "parse-error"
+	// RuleMissingDown reports migrations with up statements but an empty down section.
+	RuleMissingDown = "missing-down"
+	// RuleDropTable reports DROP TABLE in an up migration.
+	RuleDropTable = "drop-table"
+	// RuleDropColumn reports DROP COLUMN in an up migration.
+	RuleDropColumn = "drop-column"
+	// RuleCreateIndexNotConcurrently reports CREATE INDEX without CONCURRENTLY on an existing
+	// table. Postgres only.
+	RuleCreateIndexNotConcurrently = "create-index-not-concurrently"
+	// RuleConcurrentlyInTransaction reports CREATE/DROP INDEX CONCURRENTLY and REINDEX
+	// CONCURRENTLY in a migration that runs in a transaction.
+	RuleConcurrentlyInTransaction = "concurrently-in-transaction"
+	// RuleColumnTypeChange reports column type changes, which rewrite the table on most databases.
+	RuleColumnTypeChange = "column-type-change"
+	// RuleNotNullWithoutDefault reports a NOT NULL column added to an existing table without a
+	// DEFAULT.
+	RuleNotNullWithoutDefault = "not-null-without-default"
+)
+
+// Rule is a built-in lint rule.
+type Rule struct {
+	// ID is used in findings, [WithDisabledRules] and LINT IGNORE annotations.
+	ID string
+	// Description is a one-line summary of what the rule checks.
+	Description string
+	// Severity is the severity of the rule's findings.
+	Severity Severity
+	// Dialects lists the dialects the rule applies to. Empty means all dialects.
+	Dialects []database.Dialect
+
+	sqlOnly bool
+	check   func(*config, *file) []*Finding
+}
+
+// Rules returns all built-in rules, in the order they are run.
+func Rules() []Rule {
+	out := make([]Rule, 0, len(rules))
+	for _, r := range rules {
+		r := *r
+		r.Dialects = slices.Clone(r.Dialects)
+		out = append(out, r)
+	}
+	return out
+}
+
+func lookupRule(id string) (*Rule, bool) {
+	for _, r := range rules {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+var rules = []*Rule{
+	{
+		ID:          RuleParseError,
+		Description: "SQL migration cannot be parsed by goose",
+		Severity:    SeverityError,
+		sqlOnly:     true,
+		// Parse errors are reported by lintFile before any rule runs.
+		check: func(*config, *file) []*Finding { return nil },
+	},
+	{
+		ID:          RuleMissingDown,
+		Description: "migration has up statements but no down statements",
+		Severity:    SeverityWarning,
+		check:       checkMissingDown,
+	},
+	{
+		ID:          RuleDropTable,
+		Description: "DROP TABLE in an up migration",
+		Severity:    SeverityError,
+		sqlOnly:     true,
+		check:       matchUp(reDropTable, "DROP TABLE in up migration destroys data"),
+	},
+	{
+		ID:          RuleDropColumn,
+		Description: "DROP COLUMN in an up migration",
+		Severity:    SeverityError,
+		sqlOnly:     true,
+		check:       matchUp(reDropColumn, "DROP COLUMN in up migration destroys data"),
+	},
+	{
+		ID:          RuleCreateIndexNotConcurrently,
+		Description: "CREATE INDEX without CONCURRENTLY on an existing table",
+		Severity:    SeverityWarning,
+		Dialects:    []database.Dialect{database.DialectPostgres},
+		sqlOnly:     true,
+		check:       checkCreateIndexNotConcurrently,
+	},
+	{
+		ID:          RuleConcurrentlyInTransaction,
+		Description: "CONCURRENTLY index operation in a transactional migration",
+		Severity:    SeverityError,
+		sqlOnly:     true,
+		check:       checkConcurrentlyInTransaction,
+	},
+	{
+		ID:          RuleColumnTypeChange,
+		Description: "column type change on a large table",
+		Severity:    SeverityWarning,
+		sqlOnly:     true,
+		check:       checkColumnTypeChange,
+	},
+	{
+		ID:          RuleNotNullWithoutDefault,
+		Description: "NOT NULL column added to an existing table without a DEFAULT",
+		Severity:    SeverityError,
+		sqlOnly:     true,
+		check:       checkNotNullWithoutDefault,
+	},
+}
+
+var (
+	reDropTable    = regexp.MustCompile(`(?i)\bDROP\s+TABLE\b`)
+	reDropColumn   = regexp.MustCompile(`(?i)\bDROP\s+COLUMN\b`)
+	reCreateTable  = regexp.MustCompile(`(?i)\bCREATE\s+(?:(?:GLOBAL\s+|LOCAL\s+)?(?:TEMP|TEMPORARY|UNLOGGED)\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?([^\s(]+)`)
+	reCreateIndex  = regexp.MustCompile(`(?i)\bCREATE\s+(?:UNIQUE\s+)?INDEX\s+(CONCURRENTLY\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(?:[^\s(]+\s+)?ON\s+(?:ONLY\s+)?([^\s(]+)`)
+	reConcurrently = regexp.MustCompile(`(?i)\b(?:CREATE\s+(?:UNIQUE\s+)?INDEX|DROP\s+INDEX|REINDEX\b[^;]*?)\s+CONCURRENTLY\b`)
+	reAlterTable   = regexp.MustCompile(`(?i)\bALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?([^\s(]+)`)
+	reTypeChange   = regexp.MustCompile(`(?i)\bALTER\s+(?:COLUMN\s+)?[^\s,]+\s+(?:SET\s+DATA\s+)?TYPE\b|\bMODIFY\s+(?:COLUMN\s+)?[^\s,]+\s+\w|\bCHANGE\s+(?:COLUMN\s+)?[^\s,]+\s+[^\s,]+\s+\w`)
+	reAddColumn    = regexp.MustCompile(`(?i)\bADD\s+(?:COLUMN\s+)?(?:IF\s+NOT\s+EXISTS\s+)?`)
+	reAddNotColumn = regexp.MustCompile(`(?i)^(?:CONSTRAINT|PRIMARY|UNIQUE|INDEX|KEY|FOREIGN|CHECK|FULLTEXT|SPATIAL|PARTITION)\b`)
+	reNotNull      = regexp.MustCompile(`(?i)\bNOT\s+NULL\b`)
+	reDefault      = regexp.MustCompile(`(?i)\bDEFAULT\b|\bGENERATED\b|\bIDENTITY\b|\bAUTO_INCREMENT\b|\b(?:SMALL|BIG)?SERIAL\b`)
+)
+
+func checkMissingDown(_ *config, f *file) []*Finding {
+	if f.stats == nil || f.stats.UpCount == 0 || f.stats.DownCount > 0 {
+		return nil
+	}
+	line := 1
+	for i, l := range f.lines {
+		if !strings.HasPrefix(l, "--") {
+			continue
+		}
+		if a, err := sqlparser.ExtractAnnotation(l); err == nil && a == sqlparser.AnnotationDown {
+			line = i + 1
+			break
+		}
+	}
+	return []*Finding{{
+		Line:    line,
+		Message: "migration has no down statements, rolling it back will only remove the version",
+	}}
+}
+
+// matchUp returns a check that reports every match of re in the up statements.
+func matchUp(re *regexp.Regexp, message string) func(*config, *file) []*Finding {
+	return func(_ *config, f *file) []*Finding {
+		var findings []*Finding
+		for _, stmt := range f.parsed.Up {
+			for _, loc := range re.FindAllStringIndex(stripComments(stmt.SQL), -1) {
+				findings = append(findings, f.finding(stmt, loc[0], message))
+			}
+		}
+		return findings
+	}
+}
+
+func checkCreateIndexNotConcurrently(_ *config, f *file) []*Finding {
+	created := createdTables(f)
+	var findings []*Finding
+	for _, stmt := range f.parsed.Up {
+		for _, m := range reCreateIndex.FindAllStringSubmatchIndex(stripComments(stmt.SQL), -1) {
+			concurrently := m[2] >= 0
+			table := normalizeIdentifier(stmt.SQL[m[4]:m[5]])
+			if concurrently || created[table] {
+				continue
+			}
+			findings = append(findings, f.finding(stmt, m[0], fmt.Sprintf(
+				"CREATE INDEX on %s without CONCURRENTLY blocks writes while the index is built", table,
+			)))
+		}
+	}
+	return findings
+}
+
+func checkConcurrentlyInTransaction(_ *config, f *file) []*Finding {
+	if !f.parsed.UseTx {
+		return nil
+	}
+	var findings []*Finding
+	for _, stmts := range [][]*sqlparser.Statement{f.parsed.Up, f.parsed.Down} {
+		for _, stmt := range stmts {
+			for _, loc := range reConcurrently.FindAllStringIndex(stripComments(stmt.SQL), -1) {
+				findings = append(findings, f.finding(stmt, loc[0],
+					"CONCURRENTLY cannot run inside a transaction, add -- +goose NO TRANSACTION to the file",
+				))
+			}
+		}
+	}
+	return findings
+}
+
+func checkColumnTypeChange(cfg *config, f *file) []*Finding {
+	created := createdTables(f)
+	var findings []*Finding
+	for _, stmt := range f.parsed.Up {
+		sql := stripComments(stmt.SQL)
+		m := reAlterTable.FindStringSubmatchIndex(sql)
+		if m == nil {
+			continue
+		}
+		table := normalizeIdentifier(sql[m[2]:m[3]])
+		if created[table] || !cfg.isLargeTable(table) {
+			continue
+		}
+		for _, loc := range reTypeChange.FindAllStringIndex(sql[m[1]:], -1) {
+			findings = append(findings, f.finding(stmt, m[1]+loc[0], fmt.Sprintf(
+				"column type change on %s may rewrite the table and hold a lock for its duration", table,
+			)))
+		}
+	}
+	return findings
+}
+
+func checkNotNullWithoutDefault(_ *config, f *file) []*Finding {
+	created := createdTables(f)
+	var findings []*Finding
+	for _, stmt := range f.parsed.Up {
+		sql := stripComments(stmt.SQL)
+		m := reAlterTable.FindStringSubmatchIndex(sql)
+		if m == nil {
+			continue
+		}
+		table := normalizeIdentifier(sql[m[2]:m[3]])
+		if created[table] {
+			continue
+		}
+		for _, loc := range reAddColumn.FindAllStringIndex(sql[m[1]:], -1) {
+			start := m[1] + loc[1]
+			clause := sql[start:clauseEnd(sql, start)]
+			if reAddNotColumn.MatchString(clause) {
+				continue
+			}
+			if reNotNull.MatchString(clause) && !reDefault.MatchString(clause) {
+				findings = append(findings, f.finding(stmt, m[1]+loc[0], fmt.Sprintf(
+					"NOT NULL column added to %s without a DEFAULT fails if the table has rows", table,
+				)))
+			}
+		}
+	}
+	return findings
+}
+
+func (c *config) isLargeTable(table string) bool {
+	if len(c.largeTables) == 0 {
+		return true
+	}
+	if c.largeTables[table] {
+		return true
+	}
+	// Match an unqualified configured name against a schema-qualified table and vice versa.
+	_, unqualified, ok := strings.Cut(table, ".")
+	if ok && c.largeTables[unqualified] {
+		return true
+	}
+	for t := range c.largeTables {
+		if _, name, ok := strings.Cut(t, "."); ok && name == table {
+			return true
+		}
+	}
+	return false
+}
+
+// createdTables returns the tables created by the up statements of f. Changes to these tables in
+// the same migration are not reported, since the table is empty.
+func createdTables(f *file) map[string]bool {
+	created := make(map[string]bool)
+	for _, stmt := range f.parsed.Up {
+		for _, m := range reCreateTable.FindAllStringSubmatch(stripComments(stmt.SQL), -1) {
+			created[normalizeIdentifier(m[1])] = true
+		}
+	}
+	return created
+}
+
+// finding returns a finding pointing at the byte offset in stmt.SQL.
+func (f *file) finding(stmt *sqlparser.Statement, offset int, message string) *Finding {
+	before := stmt.SQL[:offset]
+	line := stmt.StartLine + strings.Count(before, "\n")
+	var column int
+	if i := strings.LastIndex(before, "\n"); i >= 0 {
+		column = offset - i
+	} else {
+		// The statement is trimmed, so add back the indentation of its first line.
+		column = offset + 1
+		if stmt.StartLine > 0 && stmt.StartLine <= len(f.lines) {
+			raw := f.lines[stmt.StartLine-1]
+			column += len(raw) - len(strings.TrimLeft(raw, " \t"))
+		}
+	}
+	return &Finding{
+		Line:    line,
+		Column:  column,
+		Message: message,
+	}
+}
+
+// stripComments replaces "--" line comments and "/* */" block comments with spaces, keeping byte
+// offsets intact. String literals are not taken into account.
+func stripComments(sql string) string {
+	b := []byte(sql)
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] == '-' && i+1 < len(b) && b[i+1] == '-':
+			for ; i < len(b) && b[i] != '\n'; i++ {
+				b[i] = ' '
+			}
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '*':
+			end := strings.Index(sql[i+2:], "*/")
+			stop := len(b)
+			if end >= 0 {
+				stop = i + 2 + end + 2
+			}
+			for ; i < stop; i++ {
+				if b[i] != '\n' {
+					b[i] = ' '
+				}
+			}
+			i--
+		}
+	}
+	return string(b)
+}
+
+// clauseEnd returns the offset of the first top-level comma or semicolon at or after start, or
+// len(sql).
+func clauseEnd(sql string, start int) int {
+	var depth int
+	for i := start; i < len(sql); i++ {
+		switch sql[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',', ';':
+			if depth <= 0 {
+				return i
+			}
+		}
+	}
+	return len(sql)
+}
+
+// normalizeIdentifier lowercases a possibly quoted, possibly schema-qualified identifier.
+func normalizeIdentifier(s string) string {
+	s = strings.NewReplacer(`"`, "", "`", "", "[", "", "]", "").Replace(s)
+	return strings.ToLower(strings.TrimSuffix(s, ";"))
+}
@@ -0,0 +1,17 @@
+package lock
+
+import (
+	"github.com/pressly/goose/v3/lock/internal/store"
+)
+
+// NewCockroachDBTableLocker returns a Locker that uses CockroachDB table-based locking. It manages a
+// single lock row and keeps the lock alive automatically, like [NewPostgresTableLocker], whose
+// defaults and options it shares.
+//
+// CockroachDB accepts pg_advisory_lock but does not implement it, so [NewPostgresSessionLocker]
+// does not serialize anything there and this locker should be used instead. Statements on the lock
+// row that CockroachDB aborts with a transaction restart error (SQLSTATE 40001), which is how
+// contention surfaces under its serializable isolation, are retried right away.
+func NewCockroachDBTableLocker(options ...TableLockerOption) (Locker, error) {
+	return newTableLocker(store.NewCockroachDB, options)
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/pressly/goose/v3/internal/cockroachdb"
+)
+
+// NewCockroachDB creates a new CockroachDB-based [LockStore]. It runs the same statements as the
+// Postgres store, which CockroachDB supports, and retries the ones CockroachDB aborts with a
+// transaction restart error.
+//
+// Under serializable isolation, instances that compete for the lock row, or a heartbeat that races
+// a competing acquisition, regularly fail with SQLSTATE 40001. These are retried right away rather
+// than at the lock retry interval, and they are not reported as a failed heartbeat.
+func NewCockroachDB(tableName string, schema Schema) (LockStore, error) {
+	if tableName == "" {
+		return nil, errors.New("table name must not be empty")
+	}
+	return &cockroachDBStore{
+		postgresStore: &postgresStore{tableName: tableName, schema: schema},
+	}, nil
+}
+
+var _ LockStore = (*cockroachDBStore)(nil)
+
+type cockroachDBStore struct {
+	*postgresStore
+}
+
+func (s *cockroachDBStore) AcquireLock(
+	ctx context.Context,
+	db *sql.DB,
+	lockID int64,
+	lockedBy string,
+	leaseDuration time.Duration,
+) (result *AcquireLockResult, err error) {
+	err = cockroachdb.Retry(ctx, func(ctx context.Context) error {
+		result, err = s.postgresStore.AcquireLock(ctx, db, lockID, lockedBy, leaseDuration)
+		return err
+	})
+	return result, err
+}
+
+func (s *cockroachDBStore) ReleaseLock(
+	ctx context.Context,
+	db *sql.DB,
+	lockID int64,
+	lockedBy string,
+) (result *ReleaseLockResult, err error) {
+	err = cockroachdb.Retry(ctx, func(ctx context.Context) error {
+		result, err = s.postgresStore.ReleaseLock(ctx, db, lockID, lockedBy)
+		return err
+	})
+	return result, err
+}
+
+func (s *cockroachDBStore) UpdateLease(
+	ctx context.Context,
+	db *sql.DB,
+	lockID int64,
+	lockedBy string,
+	leaseDuration time.Duration,
+) (result *UpdateLeaseResult, err error) {
+	err = cockroachdb.Retry(ctx, func(ctx context.Context) error {
+		result, err = s.postgresStore.UpdateLease(ctx, db, lockID, lockedBy, leaseDuration)
+		return err
+	})
+	return result, err
+}
+
+func (s *cockroachDBStore) CleanupStaleLocks(ctx context.Context, db *sql.DB) (cleaned []int64, err error) {
+	err = cockroachdb.Retry(ctx, func(ctx context.Context) error {
+		cleaned, err = s.postgresStore.CleanupStaleLocks(ctx, db)
+		return err
+	})
+	return cleaned, err
+}
@@ -8,16 +8,18 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pressly/goose/v3/internal/dialects"
 	"go.uber.org/multierr"
 )
 
 // NewPostgres creates a new Postgres-based [LockStore].
-func NewPostgres(tableName string) (LockStore, error) {
+func NewPostgres(tableName string, schema Schema) (LockStore, error) {
 	if tableName == "" {
 		return nil, errors.New("table name must not be empty")
 	}
 	return &postgresStore{
 		tableName: tableName,
+		schema:    schema,
 	}, nil
 }
 
@@ -25,6 +27,7 @@ var _ LockStore = (*postgresStore)(nil)
 
 type postgresStore struct {
 	tableName string
+	schema    Schema
 }
 
 func (s *postgresStore) TableExists(
@@ -61,6 +64,11 @@ func (s *postgresStore) CreateLockTable(
 	if exists {
 		return nil
 	}
+	if query := dialects.CreateSchema(s.tableName, s.schema.Owner); s.schema.Create && query != "" {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("create schema of lock table %q: %w", s.tableName, err)
+		}
+	}
 
 	query := fmt.Sprintf(`CREATE TABLE %s (
 		lock_id bigint NOT NULL PRIMARY KEY,
@@ -227,7 +235,7 @@ func (s *postgresStore) CheckLockStatus(
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("lock %d not found", lockID)
+			return nil, fmt.Errorf("lock %d: %w", lockID, ErrLockNotFound)
 		}
 		return nil, fmt.Errorf("check lock status for %d: %w", lockID, err)
 	}
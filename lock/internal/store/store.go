@@ -3,9 +3,22 @@ package store
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 )
 
+// ErrLockNotFound is returned by CheckLockStatus when the lock table has no row for the lock ID.
+var ErrLockNotFound = errors.New("lock not found")
+
+// Schema configures the creation of the schema of a schema-qualified lock table, such as
+// "migrations.goose_lock".
+type Schema struct {
+	// Create creates the schema, if it does not exist, before the lock table is created.
+	Create bool
+	// Owner is the owner of a new schema, or empty for the current user.
+	Owner string
+}
+
 // LockStore defines the interface for storing and managing database locks.
 type LockStore interface {
 	// CreateLockTable creates the lock table if it doesn't exist. Implementations should ensure
@@ -19,7 +32,8 @@ type LockStore interface {
 	ReleaseLock(ctx context.Context, db *sql.DB, lockID int64, lockedBy string) (*ReleaseLockResult, error)
 	// UpdateLease updates the lease expiration time for a lock (heartbeat).
 	UpdateLease(ctx context.Context, db *sql.DB, lockID int64, lockedBy string, leaseDuration time.Duration) (*UpdateLeaseResult, error)
-	// CheckLockStatus checks the current status of a lock.
+	// CheckLockStatus checks the current status of a lock. It returns [ErrLockNotFound] if the lock
+	// has never been acquired.
 	CheckLockStatus(ctx context.Context, db *sql.DB, lockID int64) (*LockStatus, error)
 	// CleanupStaleLocks removes any locks that have expired using server time. Returns the list of
 	// lock IDs that were cleaned up, if any.
@@ -14,6 +14,11 @@ type Config struct {
 	LockTimeout       ProbeConfig
 	UnlockTimeout     ProbeConfig
 
+	// CreateSchema creates the schema of a schema-qualified table, owned by SchemaOwner or by the
+	// current user if empty.
+	CreateSchema bool
+	SchemaOwner  string
+
 	// Optional logger for lock operations
 	Logger *slog.Logger
 
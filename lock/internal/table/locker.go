@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -169,6 +170,26 @@ func (l *Locker) Unlock(ctx context.Context, db *sql.DB) error {
 	return nil
 }
 
+// Status returns the current state of the lock row without acquiring the lock, or nil if the lock
+// table does not exist or the lock has never been acquired.
+func (l *Locker) Status(ctx context.Context, db *sql.DB) (*store.LockStatus, error) {
+	exists, err := l.store.TableExists(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("check lock table exists: %w", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+	status, err := l.store.CheckLockStatus(ctx, db, l.lockID)
+	if err != nil {
+		if errors.Is(err, store.ErrLockNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return status, nil
+}
+
 // startHeartbeat starts the heartbeat goroutine (called from within Lock with mutex held)
 func (l *Locker) startHeartbeat(parentCtx context.Context, db *sql.DB) {
 	// If there's already a heartbeat running, stop it first
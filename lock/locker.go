@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 )
 
 var (
@@ -30,3 +31,25 @@ type Locker interface {
 	Lock(ctx context.Context, db *sql.DB) error
 	Unlock(ctx context.Context, db *sql.DB) error
 }
+
+// LeaseChecker is implemented by table-based lockers, such as the one returned by
+// [NewPostgresTableLocker], to report the state of the lock without acquiring it.
+type LeaseChecker interface {
+	// LeaseStatus returns the current state of the lock, or nil if the lock table does not exist
+	// or the lock has never been acquired.
+	LeaseStatus(ctx context.Context, db *sql.DB) (*LeaseStatus, error)
+}
+
+// LeaseStatus is the state of a table-based lock.
+type LeaseStatus struct {
+	// Locked reports whether the lock is held. A held lock whose lease has expired is stale: the
+	// instance holding it stopped renewing the lease, and the lock is reclaimed the next time it is
+	// acquired.
+	Locked bool
+	// LockedBy identifies the instance holding the lock, if any.
+	LockedBy string
+	// LeaseExpiresAt is when the lease of the lock expires unless it is renewed.
+	LeaseExpiresAt time.Time
+	// UpdatedAt is when the lock was last acquired, renewed or released.
+	UpdatedAt time.Time
+}
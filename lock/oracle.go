@@ -0,0 +1,118 @@
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sethvargo/go-retry"
+)
+
+// NewOracleSessionLocker returns a SessionLocker that utilizes Oracle's exclusive user locks from
+// the DBMS_LOCK package. The lock is named after the lock ID, "goose_lock_4097083626" by default,
+// and is held by the session until it is released or the session ends.
+//
+// The user must be granted EXECUTE on DBMS_LOCK, e.g., GRANT EXECUTE ON SYS.DBMS_LOCK TO goose.
+// Allocating the lock name commits the transaction of the session, which is why the lock is taken
+// before migrations run and released after they completed.
+//
+// The defaults and options are those of [NewPostgresSessionLocker], see [SessionLockerOption].
+func NewOracleSessionLocker(opts ...SessionLockerOption) (SessionLocker, error) {
+	cfg := sessionLockerConfig{
+		lockID: DefaultLockID,
+		lockProbe: probe{
+			intervalDuration: 5 * time.Second,
+			failureThreshold: 60,
+		},
+		unlockProbe: probe{
+			intervalDuration: 2 * time.Second,
+			failureThreshold: 30,
+		},
+	}
+	for _, opt := range opts {
+		if err := opt.apply(&cfg); err != nil {
+			return nil, err
+		}
+	}
+	return &oracleSessionLocker{
+		lockName: fmt.Sprintf("goose_lock_%d", cfg.lockID),
+		retryLock: retry.WithMaxRetries(
+			cfg.lockProbe.failureThreshold,
+			retry.NewConstant(cfg.lockProbe.intervalDuration),
+		),
+		retryUnlock: retry.WithMaxRetries(
+			cfg.unlockProbe.failureThreshold,
+			retry.NewConstant(cfg.unlockProbe.intervalDuration),
+		),
+	}, nil
+}
+
+type oracleSessionLocker struct {
+	lockName    string
+	retryLock   retry.Backoff
+	retryUnlock retry.Backoff
+}
+
+var _ SessionLocker = (*oracleSessionLocker)(nil)
+
+// Result codes of DBMS_LOCK.REQUEST and DBMS_LOCK.RELEASE.
+const (
+	dbmsLockSuccess  = 0
+	dbmsLockTimeout  = 1
+	dbmsLockDeadlock = 2
+	// dbmsLockAlreadyOwned is returned by REQUEST if the session already holds the lock.
+	dbmsLockAlreadyOwned = 4
+	// dbmsLockNotOwned is returned by RELEASE if the session does not hold the lock.
+	dbmsLockNotOwned = 4
+)
+
+// The lock is requested with a timeout of 0 seconds, the locker retries on its own schedule.
+const (
+	oracleRequestLock = `DECLARE
+  handle VARCHAR2(128);
+BEGIN
+  DBMS_LOCK.ALLOCATE_UNIQUE(:1, handle);
+  :2 := DBMS_LOCK.REQUEST(lockhandle => handle, lockmode => DBMS_LOCK.X_MODE, timeout => 0, release_on_commit => FALSE);
+END;`
+	oracleReleaseLock = `DECLARE
+  handle VARCHAR2(128);
+BEGIN
+  DBMS_LOCK.ALLOCATE_UNIQUE(:1, handle);
+  :2 := DBMS_LOCK.RELEASE(lockhandle => handle);
+END;`
+)
+
+func (l *oracleSessionLocker) SessionLock(ctx context.Context, conn *sql.Conn) error {
+	return retry.Do(ctx, l.retryLock, func(ctx context.Context) error {
+		var result int64
+		if _, err := conn.ExecContext(ctx, oracleRequestLock, l.lockName, sql.Out{Dest: &result}); err != nil {
+			return fmt.Errorf("failed to execute DBMS_LOCK.REQUEST: %w", err)
+		}
+		switch result {
+		case dbmsLockSuccess, dbmsLockAlreadyOwned:
+			return nil
+		case dbmsLockTimeout, dbmsLockDeadlock:
+			// Another session holds the lock. We will continue retrying until the lock is
+			// acquired or the maximum number of retries is reached.
+			return retry.RetryableError(errors.New("failed to acquire lock"))
+		}
+		return fmt.Errorf("DBMS_LOCK.REQUEST failed with result %d", result)
+	})
+}
+
+func (l *oracleSessionLocker) SessionUnlock(ctx context.Context, conn *sql.Conn) error {
+	return retry.Do(ctx, l.retryUnlock, func(ctx context.Context) error {
+		var result int64
+		if _, err := conn.ExecContext(ctx, oracleReleaseLock, l.lockName, sql.Out{Dest: &result}); err != nil {
+			return fmt.Errorf("failed to execute DBMS_LOCK.RELEASE: %w", err)
+		}
+		switch result {
+		case dbmsLockSuccess, dbmsLockNotOwned:
+			// A session that does not hold the lock has nothing to release.
+			return nil
+		}
+		return fmt.Errorf("DBMS_LOCK.RELEASE failed with result %d", result)
+	})
+}
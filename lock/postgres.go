@@ -31,6 +31,15 @@ import (
 // Lock and Unlock both retry on failure. Lock stays alive automatically until released. All
 // defaults can be overridden with options.
 func NewPostgresTableLocker(options ...TableLockerOption) (Locker, error) {
+	return newTableLocker(store.NewPostgres, options)
+}
+
+// newTableLocker returns a table-based locker with the defaults documented on
+// [NewPostgresTableLocker], backed by the lock store returned by newStore.
+func newTableLocker(
+	newStore func(tableName string, schema store.Schema) (store.LockStore, error),
+	options []TableLockerOption,
+) (Locker, error) {
 	config := table.Config{
 		TableName:         DefaultLockTableName,
 		LockID:            DefaultLockID,
@@ -50,11 +59,39 @@ func NewPostgresTableLocker(options ...TableLockerOption) (Locker, error) {
 			return nil, err
 		}
 	}
-	lockStore, err := store.NewPostgres(config.TableName)
+	lockStore, err := newStore(config.TableName, store.Schema{Create: config.CreateSchema, Owner: config.SchemaOwner})
 	if err != nil {
 		return nil, fmt.Errorf("create lock store: %w", err)
 	}
-	return table.New(lockStore, config), nil
+	return &tableLocker{Locker: table.New(lockStore, config)}, nil
+}
+
+// tableLocker adds the [LeaseChecker] interface to a table-based locker.
+type tableLocker struct {
+	*table.Locker
+}
+
+var (
+	_ Locker       = (*tableLocker)(nil)
+	_ LeaseChecker = (*tableLocker)(nil)
+)
+
+func (l *tableLocker) LeaseStatus(ctx context.Context, db *sql.DB) (*LeaseStatus, error) {
+	status, err := l.Status(ctx, db)
+	if err != nil || status == nil {
+		return nil, err
+	}
+	lease := &LeaseStatus{Locked: status.Locked}
+	if status.LockedBy != nil {
+		lease.LockedBy = *status.LockedBy
+	}
+	if status.LeaseExpiresAt != nil {
+		lease.LeaseExpiresAt = *status.LeaseExpiresAt
+	}
+	if status.UpdatedAt != nil {
+		lease.UpdatedAt = *status.UpdatedAt
+	}
+	return lease, nil
 }
 
 // NewPostgresSessionLocker returns a SessionLocker that utilizes PostgreSQL's exclusive
@@ -0,0 +1,157 @@
+package lock
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pressly/goose/v3/database"
+)
+
+// LockerConfig is the configuration of a locker created by [NewSessionLocker] or [NewLocker].
+type LockerConfig struct {
+	// LockID identifies the lock. Zero keeps the default lock ID of the locker, usually
+	// [DefaultLockID].
+	LockID int64
+	// LockTimeout is how long to wait for a lock held by another instance. Zero keeps the default
+	// timeout of the locker.
+	LockTimeout time.Duration
+
+	// The fields below only apply to table-based lockers.
+
+	// TableName is the name of the lock table. Empty keeps [DefaultLockTableName].
+	TableName string
+	// CreateSchema creates the schema of a schema-qualified TableName, owned by SchemaOwner or by
+	// the current user if empty, see [WithTableCreateSchema].
+	CreateSchema bool
+	SchemaOwner  string
+}
+
+// lockRetryInterval is how often a held lock is retried until the LockTimeout of a LockerConfig
+// expires.
+const lockRetryInterval = 5 * time.Second
+
+var (
+	registryMu     sync.RWMutex
+	sessionLockers = map[database.Dialect]func(LockerConfig) (SessionLocker, error){
+		database.DialectPostgres: func(cfg LockerConfig) (SessionLocker, error) {
+			return NewPostgresSessionLocker(sessionLockerOptions(cfg)...)
+		},
+		database.DialectOracle: func(cfg LockerConfig) (SessionLocker, error) {
+			return NewOracleSessionLocker(sessionLockerOptions(cfg)...)
+		},
+	}
+	lockers = map[database.Dialect]func(LockerConfig) (Locker, error){
+		database.DialectPostgres: func(cfg LockerConfig) (Locker, error) {
+			return NewPostgresTableLocker(tableLockerOptions(cfg)...)
+		},
+		database.DialectCockroachDB: func(cfg LockerConfig) (Locker, error) {
+			return NewCockroachDBTableLocker(tableLockerOptions(cfg)...)
+		},
+	}
+)
+
+// RegisterSessionLocker makes a session locker available for a dialect to [NewSessionLocker], and
+// with it to the -lock session flag of the goose CLI. It is meant to be called from the init
+// function of a package that adds support for a database, next to [database.RegisterDialect].
+//
+// If RegisterSessionLocker is called twice for the same dialect, or if newLocker is nil, it panics.
+func RegisterSessionLocker(d database.Dialect, newLocker func(LockerConfig) (SessionLocker, error)) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	register(sessionLockers, "RegisterSessionLocker", d, newLocker)
+}
+
+// RegisterLocker makes a locker available for a dialect to [NewLocker], and with it to the -lock
+// table flag of the goose CLI. See [RegisterSessionLocker].
+//
+// If RegisterLocker is called twice for the same dialect, or if newLocker is nil, it panics.
+func RegisterLocker(d database.Dialect, newLocker func(LockerConfig) (Locker, error)) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	register(lockers, "RegisterLocker", d, newLocker)
+}
+
+func register[T any](
+	m map[database.Dialect]func(LockerConfig) (T, error),
+	fn string,
+	d database.Dialect,
+	newLocker func(LockerConfig) (T, error),
+) {
+	if newLocker == nil {
+		panic("goose: " + fn + " function is nil")
+	}
+	if _, dup := m[d]; dup {
+		panic("goose: " + fn + " called twice for dialect " + string(d))
+	}
+	m[d] = newLocker
+}
+
+// NewSessionLocker returns a session locker for a dialect. Postgres and Oracle are built in, other
+// dialects are added with [RegisterSessionLocker]. It returns an error wrapping [ErrLockNotImplemented] if
+// the dialect has no session locker.
+func NewSessionLocker(d database.Dialect, cfg LockerConfig) (SessionLocker, error) {
+	registryMu.RLock()
+	newLocker, ok := sessionLockers[d]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("session locker for dialect %q: %w", d, ErrLockNotImplemented)
+	}
+	return newLocker(cfg)
+}
+
+// NewLocker returns a locker for a dialect. Postgres and CockroachDB are built in, other dialects
+// are added with [RegisterLocker]. It returns an error wrapping [ErrLockNotImplemented] if the
+// dialect has no locker.
+func NewLocker(d database.Dialect, cfg LockerConfig) (Locker, error) {
+	registryMu.RLock()
+	newLocker, ok := lockers[d]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("locker for dialect %q: %w", d, ErrLockNotImplemented)
+	}
+	return newLocker(cfg)
+}
+
+// lockProbe splits a lock timeout into a retry interval of whole seconds and the number of
+// attempts needed to cover the timeout, for lockers that retry at a fixed interval, such as
+// [WithLockTimeout] and [WithTableLockTimeout].
+func lockProbe(timeout time.Duration) (interval time.Duration, failureThreshold uint64) {
+	interval = lockRetryInterval
+	if timeout < interval {
+		interval = time.Second
+	}
+	failureThreshold = uint64((timeout + interval - 1) / interval)
+	return interval, max(failureThreshold, 1)
+}
+
+// sessionLockerOptions returns the options of the built-in session lockers for cfg.
+func sessionLockerOptions(cfg LockerConfig) []SessionLockerOption {
+	var opts []SessionLockerOption
+	if cfg.LockID != 0 {
+		opts = append(opts, WithLockID(cfg.LockID))
+	}
+	if cfg.LockTimeout > 0 {
+		interval, threshold := lockProbe(cfg.LockTimeout)
+		opts = append(opts, WithLockTimeout(uint64(interval/time.Second), threshold))
+	}
+	return opts
+}
+
+// tableLockerOptions returns the options of the built-in table lockers for cfg.
+func tableLockerOptions(cfg LockerConfig) []TableLockerOption {
+	var opts []TableLockerOption
+	if cfg.LockID != 0 {
+		opts = append(opts, WithTableLockID(cfg.LockID))
+	}
+	if cfg.LockTimeout > 0 {
+		opts = append(opts, WithTableLockTimeout(lockProbe(cfg.LockTimeout)))
+	}
+	if cfg.TableName != "" {
+		opts = append(opts, WithTableName(cfg.TableName))
+	}
+	if cfg.CreateSchema {
+		opts = append(opts, WithTableCreateSchema(cfg.SchemaOwner))
+	}
+	return opts
+}
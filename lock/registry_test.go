@@ -0,0 +1,89 @@
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/pressly/goose/v3/database"
+	"github.com/stretchr/testify/require"
+)
+
+type testLocker struct {
+	cfg LockerConfig
+}
+
+func (l *testLocker) Lock(context.Context, *sql.DB) error   { return nil }
+func (l *testLocker) Unlock(context.Context, *sql.DB) error { return nil }
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("postgres", func(t *testing.T) {
+		sessionLocker, err := NewSessionLocker(database.DialectPostgres, LockerConfig{LockID: 42})
+		require.NoError(t, err)
+		require.Equal(t, int64(42), sessionLocker.(*postgresSessionLocker).lockID)
+		locker, err := NewLocker(database.DialectPostgres, LockerConfig{})
+		require.NoError(t, err)
+		require.Implements(t, (*LeaseChecker)(nil), locker)
+	})
+	t.Run("oracle", func(t *testing.T) {
+		sessionLocker, err := NewSessionLocker(database.DialectOracle, LockerConfig{LockID: 42})
+		require.NoError(t, err)
+		require.Equal(t, "goose_lock_42", sessionLocker.(*oracleSessionLocker).lockName)
+		_, err = NewLocker(database.DialectOracle, LockerConfig{})
+		require.ErrorIs(t, err, ErrLockNotImplemented)
+	})
+	t.Run("cockroachdb", func(t *testing.T) {
+		locker, err := NewLocker(database.DialectCockroachDB, LockerConfig{})
+		require.NoError(t, err)
+		require.Implements(t, (*LeaseChecker)(nil), locker)
+		// pg_advisory_lock is a no-op on CockroachDB.
+		_, err = NewSessionLocker(database.DialectCockroachDB, LockerConfig{})
+		require.ErrorIs(t, err, ErrLockNotImplemented)
+	})
+	t.Run("not implemented", func(t *testing.T) {
+		_, err := NewSessionLocker(database.DialectSQLite3, LockerConfig{})
+		require.ErrorIs(t, err, ErrLockNotImplemented)
+		_, err = NewLocker(database.DialectSQLite3, LockerConfig{})
+		require.ErrorIs(t, err, ErrLockNotImplemented)
+	})
+	t.Run("register", func(t *testing.T) {
+		const dialect database.Dialect = "registry_test"
+		RegisterLocker(dialect, func(cfg LockerConfig) (Locker, error) {
+			return &testLocker{cfg: cfg}, nil
+		})
+		cfg := LockerConfig{LockID: 7, LockTimeout: time.Minute}
+		locker, err := NewLocker(dialect, cfg)
+		require.NoError(t, err)
+		require.Equal(t, cfg, locker.(*testLocker).cfg)
+		_, err = NewSessionLocker(dialect, cfg)
+		require.ErrorIs(t, err, ErrLockNotImplemented)
+
+		require.Panics(t, func() {
+			RegisterLocker(dialect, func(LockerConfig) (Locker, error) { return nil, nil })
+		})
+		require.Panics(t, func() { RegisterSessionLocker(dialect, nil) })
+	})
+}
+
+func TestLockProbe(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		timeout   time.Duration
+		interval  time.Duration
+		threshold uint64
+	}{
+		{500 * time.Millisecond, time.Second, 1},
+		{3 * time.Second, time.Second, 3},
+		{5 * time.Second, 5 * time.Second, 1},
+		{32 * time.Second, 5 * time.Second, 7},
+		{5 * time.Minute, 5 * time.Second, 60},
+	} {
+		interval, threshold := lockProbe(tt.timeout)
+		require.Equal(t, tt.interval, interval, tt.timeout)
+		require.Equal(t, tt.threshold, threshold, tt.timeout)
+	}
+}
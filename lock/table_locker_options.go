@@ -30,6 +30,17 @@ func WithTableName(tableName string) TableLockerOption {
 	})
 }
 
+// WithTableCreateSchema creates the schema of a schema-qualified lock table, such as
+// "migrations.goose_lock" set with [WithTableName], if it does not exist. owner is the owner of a
+// new schema, or empty for the current user.
+func WithTableCreateSchema(owner string) TableLockerOption {
+	return tableLockerConfigFunc(func(c *table.Config) error {
+		c.CreateSchema = true
+		c.SchemaOwner = owner
+		return nil
+	})
+}
+
 // WithTableLockID sets the lock ID to use for this locker instance. Different lock IDs allow for
 // multiple independent locks in the same table.
 func WithTableLockID(lockID int64) TableLockerOption {
@@ -17,6 +17,13 @@ func TestTableLockerOptions(t *testing.T) {
 	)
 	require.NoError(t, err)
 	require.NotNil(t, locker)
+	// Test creating the schema of a schema-qualified lock table
+	locker, err = NewPostgresTableLocker(
+		WithTableName("migrations.goose_lock"),
+		WithTableCreateSchema("app"),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, locker)
 	// Test invalid lease duration
 	_, err = NewPostgresTableLocker(WithTableLeaseDuration(-1 * time.Second))
 	require.Error(t, err)
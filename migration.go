@@ -220,8 +220,13 @@ func (m *Migration) run(ctx context.Context, db *sql.DB, direction bool) error {
 		}
 		defer f.Close()
 
-		statements, useTx, err := sqlparser.ParseSQLMigration(f, sqlparser.FromBool(direction), verbose)
+		statements, useTx, err := sqlparser.ParseSQLMigration(f, sqlparser.FromBool(direction), verbose, sqlParseOptions(storeDialect)...)
 		if err != nil {
+			// A parse error reports the path of the file itself.
+			var parseErr *sqlparser.ParseError
+			if errors.As(err, &parseErr) {
+				return fmt.Errorf("ERROR: failed to parse SQL migration file: %w", sqlparser.WithPath(err, m.Source))
+			}
 			return fmt.Errorf("ERROR %v: failed to parse SQL migration file: %w", filepath.Base(m.Source), err)
 		}
 
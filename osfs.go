@@ -1,9 +1,12 @@
 package goose
 
 import (
+	"cmp"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 )
 
 // osFS wraps functions working with os filesystem to implement fs.FS interfaces.
@@ -26,3 +29,36 @@ var _ fs.FS = noopFS{}
 func (f noopFS) Open(name string) (fs.File, error) {
 	return nil, os.ErrNotExist
 }
+
+// namedFS is an additional filesystem of migration files, see [WithAdditionalFS].
+type namedFS struct {
+	name string
+	fsys fs.FS
+}
+
+// unionFS serves the files of each additional filesystem below its name, and all other files from
+// the root filesystem.
+type unionFS struct {
+	root fs.FS
+	dirs []namedFS
+}
+
+var _ fs.FS = (*unionFS)(nil)
+
+func newUnionFS(root fs.FS, dirs []namedFS) *unionFS {
+	// Match the longest name first, so "a/b" takes precedence over "a".
+	dirs = slices.Clone(dirs)
+	slices.SortFunc(dirs, func(a, b namedFS) int {
+		return cmp.Compare(len(b.name), len(a.name))
+	})
+	return &unionFS{root: root, dirs: dirs}
+}
+
+func (u *unionFS) Open(name string) (fs.File, error) {
+	for _, d := range u.dirs {
+		if rest, ok := strings.CutPrefix(name, d.name+"/"); ok {
+			return d.fsys.Open(rest)
+		}
+	}
+	return u.root.Open(name)
+}
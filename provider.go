@@ -10,6 +10,7 @@ import (
 	"log/slog"
 	"maps"
 	"math"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -28,6 +29,7 @@ type Provider struct {
 	mu sync.Mutex
 
 	db               *sql.DB
+	storeDB          *sql.DB // version table and lock, db unless WithStoreDB is used
 	store            *controller.StoreController
 	versionTableOnce sync.Once
 
@@ -72,12 +74,18 @@ func NewProvider(dialect Dialect, db *sql.DB, fsys fs.FS, opts ...ProviderOption
 			return nil, err
 		}
 	}
+	// The store is selected by the dialect of the store database, which is the database migrations
+	// run against unless WithStoreDB is used.
+	storeDialect := dialect
+	if cfg.storeDB != nil {
+		storeDialect = cfg.storeDialect
+	}
 	// Allow users to specify a custom store implementation, but only if they don't specify a
 	// dialect. If they specify a dialect, we'll use the default store implementation.
-	if dialect == DialectCustom && cfg.store == nil {
+	if storeDialect == DialectCustom && cfg.store == nil {
 		return nil, errors.New("custom store must be supplied when using a custom dialect, make sure to pass WithStore option")
 	}
-	if dialect != DialectCustom && cfg.store != nil {
+	if storeDialect != DialectCustom && cfg.store != nil {
 		return nil, errors.New("custom store must not be specified when using one of the default dialects, use DialectCustom instead")
 	}
 	// Allow table name to be set only if store is not set.
@@ -89,10 +97,34 @@ func NewProvider(dialect Dialect, db *sql.DB, fsys fs.FS, opts ...ProviderOption
 	if cfg.slogger == nil && cfg.logger == nil {
 		cfg.logger = &stdLogger{}
 	}
+	if cfg.clickhouse != nil && dialect != DialectClickHouse && storeDialect != DialectClickHouse {
+		return nil, errors.New("WithClickHouse requires the clickhouse dialect")
+	}
+	cfg.sqlParseOptions = sqlParseOptions(dialect)
+	cfg.retryTx = dialect == DialectCockroachDB
+	if cfg.clickhouse != nil && dialect == DialectClickHouse {
+		cfg.sqlParseOptions = append(cfg.sqlParseOptions, clickhouseParseOption(cfg.clickhouse.Cluster))
+	}
+	if cfg.createSchema && cfg.store != nil {
+		return nil, errors.New("WithCreateSchema cannot be used with WithStore")
+	}
+	var storeOpts []database.StoreOption
+	if cfg.createSchema {
+		storeOpts = append(storeOpts, database.WithCreateSchema(cfg.schemaOwner))
+	}
 	var store database.Store
-	if dialect != "" {
+	if storeDialect == DialectClickHouse && cfg.clickhouse != nil {
+		if cfg.createSchema {
+			return nil, errors.New("WithCreateSchema is not supported by the clickhouse dialect")
+		}
 		var err error
-		store, err = database.NewStore(dialect, cmp.Or(cfg.tableName, DefaultTablename))
+		store, err = database.NewClickHouseStore(cmp.Or(cfg.tableName, DefaultTablename), *cfg.clickhouse)
+		if err != nil {
+			return nil, err
+		}
+	} else if storeDialect != "" {
+		var err error
+		store, err = database.NewStore(storeDialect, cmp.Or(cfg.tableName, DefaultTablename), storeOpts...)
 		if err != nil {
 			return nil, err
 		}
@@ -123,6 +155,18 @@ func newProvider(
 	if err != nil {
 		return nil, err
 	}
+	for _, f := range cfg.additionalFS {
+		sources, err := collectFilesystemSources(f.fsys, false, cfg.excludePaths, cfg.excludeVersions)
+		if err != nil {
+			return nil, fmt.Errorf("additional filesystem %q: %w", f.name, err)
+		}
+		if err := filesystemSources.add(f.name, sources); err != nil {
+			return nil, err
+		}
+	}
+	if len(cfg.additionalFS) > 0 {
+		fsys = newUnionFS(fsys, cfg.additionalFS)
+	}
 	versionToGoMigration := make(map[int64]*Migration)
 	// Add user-registered Go migrations from the provider.
 	maps.Copy(versionToGoMigration, cfg.registered)
@@ -149,6 +193,7 @@ func newProvider(
 	}
 	return &Provider{
 		db:         db,
+		storeDB:    cmp.Or(cfg.storeDB, db),
 		fsys:       fsys,
 		cfg:        cfg,
 		store:      controller.NewStoreController(store),
@@ -189,6 +234,56 @@ func (p *Provider) GetDBVersion(ctx context.Context) (int64, error) {
 	return p.getDBMaxVersion(ctx, nil)
 }
 
+// History returns every row of the version table in insertion order, oldest first. Unlike
+// [Provider.Status], which reports one state per migration, it shows each up and down that was
+// recorded, e.g., to debug a rollback.
+//
+// The store must implement [database.HistoryLister], as the stores of all built-in dialects do.
+// Otherwise, an error that wraps [errors.ErrUnsupported] is returned.
+//
+// Note, this method will not use a SessionLocker or Locker if one is configured.
+func (p *Provider) History(ctx context.Context) ([]*HistoryEntry, error) {
+	if p.cfg.disableVersioning {
+		return nil, errors.New("history not supported when versioning is disabled")
+	}
+	return p.history(ctx)
+}
+
+// Doctor inspects the version table and the migration sources for problems that do not stop
+// goose from running, but usually point to a mistake, such as a version applied twice, a missing
+// migration file or a lock left behind by a crashed process. It returns the problems found, or an
+// empty slice if there are none. See [DoctorCheck] for the checks.
+//
+// A migration that was changed from Go to SQL, or the other way around, after it was applied is
+// only detected if the store records migration types, see [database.TypeLister]. The Postgres and
+// SQLite stores do for version tables created or upgraded by this release.
+//
+// Rows recorded in the future are only detected if the store compares timestamps with the clock of
+// the database, see [database.FutureHistoryLister], as the stores of the built-in dialects do.
+//
+// Note, this method will not use a SessionLocker or Locker if one is configured. A Locker that
+// implements [lock.LeaseChecker] is only used to check for a stale lock.
+func (p *Provider) Doctor(ctx context.Context) ([]*DoctorFinding, error) {
+	if p.cfg.disableVersioning {
+		return nil, errors.New("doctor not supported when versioning is disabled")
+	}
+	return p.doctor(ctx)
+}
+
+// UpgradeTable upgrades the layout of the version table, e.g., a column or an index added by a
+// newer goose release, and returns the layout before and after the upgrade. The upgrade runs under
+// the SessionLocker or Locker if one is configured.
+//
+// The version table is never upgraded implicitly. Operations that apply migrations work with
+// the layout the table has, so the upgrade can be rolled out on its own, e.g., ahead of a
+// deployment.
+func (p *Provider) UpgradeTable(ctx context.Context) (*database.TableUpgradeResult, error) {
+	if p.cfg.disableVersioning {
+		return nil, errors.New("upgrade table not supported when versioning is disabled")
+	}
+	return p.upgradeTable(ctx)
+}
+
 // ListSources returns a list of all migration sources known to the provider, sorted in ascending
 // order by version. The path field may be empty for manually registered migrations, such as Go
 // migrations registered using the [WithGoMigrations] option.
@@ -204,12 +299,20 @@ func (p *Provider) ListSources() []*Source {
 	return sources
 }
 
-// Ping attempts to ping the database to verify a connection is available.
+// Ping attempts to ping the database, and the database of [WithStoreDB] if set, to verify a
+// connection is available.
 func (p *Provider) Ping(ctx context.Context) error {
-	return p.db.PingContext(ctx)
+	if err := p.db.PingContext(ctx); err != nil {
+		return err
+	}
+	if p.storeDB != p.db {
+		return p.storeDB.PingContext(ctx)
+	}
+	return nil
 }
 
-// Close closes the database connection initially supplied to the provider.
+// Close closes the database connection initially supplied to the provider. The database of
+// [WithStoreDB] is left open.
 func (p *Provider) Close() error {
 	return p.db.Close()
 }
@@ -342,6 +445,17 @@ func (p *Provider) DownTo(ctx context.Context, version int64) ([]*MigrationResul
 	return p.down(ctx, false, version)
 }
 
+// Redo rolls back the most recently applied migration and applies it again. Both steps run under a
+// single acquisition of the SessionLocker or Locker, if one is configured, so no other process can
+// migrate the database in between. If there are no migrations to rollback, this method returns
+// [ErrNoNextVersion].
+//
+// The results are the rollback followed by the migration applied again. If applying the migration
+// again fails, the rollback is part of the applied results of the [PartialError].
+func (p *Provider) Redo(ctx context.Context) ([]*MigrationResult, error) {
+	return p.redo(ctx)
+}
+
 // *** Internal methods ***
 
 func (p *Provider) up(
@@ -465,6 +579,50 @@ func (p *Provider) down(
 	return p.runMigrations(ctx, conn, apply, sqlparser.DirectionDown, byOne)
 }
 
+func (p *Provider) redo(ctx context.Context) (_ []*MigrationResult, retErr error) {
+	conn, cleanup, err := p.initialize(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize: %w", err)
+	}
+	defer func() {
+		retErr = multierr.Append(retErr, cleanup())
+	}()
+
+	if len(p.migrations) == 0 {
+		return nil, ErrNoNextVersion
+	}
+	m := p.migrations[len(p.migrations)-1]
+	if !p.cfg.disableVersioning {
+		dbMigrations, err := p.store.ListMigrations(ctx, conn)
+		if err != nil {
+			return nil, err
+		}
+		if len(dbMigrations) == 0 {
+			return nil, errMissingZeroVersion
+		}
+		// We never migrate the zero version down.
+		if dbMigrations[0].Version == 0 {
+			return nil, ErrNoNextVersion
+		}
+		if m, err = p.getMigration(dbMigrations[0].Version); err != nil {
+			return nil, err
+		}
+	}
+	down, err := p.runMigrations(ctx, conn, []*Migration{m}, sqlparser.DirectionDown, true)
+	if err != nil {
+		return nil, err
+	}
+	up, err := p.runMigrations(ctx, conn, []*Migration{m}, sqlparser.DirectionUp, true)
+	if err != nil {
+		var partialErr *PartialError
+		if errors.As(err, &partialErr) {
+			partialErr.Applied = slices.Concat(down, partialErr.Applied)
+		}
+		return nil, err
+	}
+	return slices.Concat(down, up), nil
+}
+
 func (p *Provider) apply(
 	ctx context.Context,
 	version int64,
@@ -643,6 +801,62 @@ func (p *Provider) status(ctx context.Context) (_ []*MigrationStatus, retErr err
 	return status, nil
 }
 
+func (p *Provider) upgradeTable(ctx context.Context) (_ *database.TableUpgradeResult, retErr error) {
+	conn, cleanup, err := p.initialize(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize: %w", err)
+	}
+	defer func() {
+		retErr = multierr.Append(retErr, cleanup())
+	}()
+	res, err := p.store.UpgradeTable(ctx, conn)
+	if errors.Is(err, errors.ErrUnsupported) {
+		return nil, fmt.Errorf("store does not support upgrading the version table: %w", err)
+	} else if err != nil {
+		return nil, fmt.Errorf("upgrade version table: %w", err)
+	}
+	if res.From != res.To {
+		p.logf(ctx,
+			fmt.Sprintf("upgraded version table from layout %d to %d", res.From, res.To),
+			"upgraded version table",
+			slog.Int("from_layout", res.From),
+			slog.Int("to_layout", res.To),
+		)
+	}
+	return res, nil
+}
+
+func (p *Provider) history(ctx context.Context) (_ []*HistoryEntry, retErr error) {
+	conn, cleanup, err := p.initialize(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize: %w", err)
+	}
+	defer func() {
+		retErr = multierr.Append(retErr, cleanup())
+	}()
+
+	rows, err := p.store.ListHistory(ctx, conn)
+	if errors.Is(err, errors.ErrUnsupported) {
+		return nil, fmt.Errorf("store does not support listing the version table history: %w", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	history := make([]*HistoryEntry, 0, len(rows))
+	for _, row := range rows {
+		entry := &HistoryEntry{
+			Version:   row.Version,
+			IsApplied: row.IsApplied,
+			Timestamp: row.Timestamp,
+		}
+		if m, err := p.getMigration(row.Version); err == nil {
+			entry.Source = &Source{Type: m.Type, Path: m.Source, Version: m.Version}
+		}
+		history = append(history, entry)
+	}
+	return history, nil
+}
+
 // getDBMaxVersion returns the highest version recorded in the database, regardless of the order in
 // which migrations were applied. conn may be nil, in which case a connection is initialized.
 func (p *Provider) getDBMaxVersion(ctx context.Context, conn *sql.Conn) (_ int64, retErr error) {
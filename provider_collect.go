@@ -1,10 +1,13 @@
 package goose
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"io/fs"
+	"path"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -100,6 +103,38 @@ func collectFilesystemSources(
 	return sources, nil
 }
 
+// add adds the sources of an additional filesystem, prefixing their paths with its name. Versions
+// must be unique across all filesystems.
+func (s *fileSources) add(name string, other *fileSources) error {
+	existing := make(map[int64]string)
+	for _, src := range slices.Concat(s.sqlSources, s.goSources) {
+		existing[src.Version] = src.Path
+	}
+	for _, list := range []*[]Source{&other.sqlSources, &other.goSources} {
+		for _, src := range *list {
+			src.Path = path.Join(name, src.Path)
+			if fullpath, ok := existing[src.Version]; ok {
+				return fmt.Errorf("found duplicate migration version %d:\n\texisting:%v\n\tcurrent:%v",
+					src.Version,
+					fullpath,
+					src.Path,
+				)
+			}
+			existing[src.Version] = src.Path
+			if src.Type == TypeSQL {
+				s.sqlSources = append(s.sqlSources, src)
+			} else {
+				s.goSources = append(s.goSources, src)
+			}
+		}
+	}
+	// Order the merged sources by version.
+	byVersion := func(a, b Source) int { return cmp.Compare(a.Version, b.Version) }
+	slices.SortFunc(s.sqlSources, byVersion)
+	slices.SortFunc(s.goSources, byVersion)
+	return nil
+}
+
 func newSQLMigration(source Source) *Migration {
 	return &Migration{
 		Type:      source.Type,
@@ -0,0 +1,203 @@
+package goose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pressly/goose/v3/database"
+	"github.com/pressly/goose/v3/lock"
+	"go.uber.org/multierr"
+)
+
+func (p *Provider) doctor(ctx context.Context) ([]*DoctorFinding, error) {
+	history, err := p.history(ctx)
+	if err != nil {
+		return nil, err
+	}
+	findings := checkHistory(history)
+	future, err := p.futureHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, checkFuture(future)...)
+	types, err := p.migrationTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, checkTypes(p.migrations, types)...)
+	findings = append(findings, checkVersionGaps(p.migrations)...)
+	if checker, ok := p.cfg.locker.(lock.LeaseChecker); ok {
+		status, err := checker.LeaseStatus(ctx, p.storeDB)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check lock: %w", err)
+		}
+		if f := checkLease(status, time.Now()); f != nil {
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
+
+// checkHistory checks the rows of the version table, oldest first.
+func checkHistory(history []*HistoryEntry) []*DoctorFinding {
+	findings := make([]*DoctorFinding, 0)
+	var hasZero bool
+	applied := make(map[int64]bool)
+	for _, e := range history {
+		if e.Version == 0 {
+			hasZero = true
+		}
+		if e.IsApplied && applied[e.Version] && e.Version != 0 {
+			findings = append(findings, &DoctorFinding{
+				Check:   CheckDuplicateVersion,
+				Version: e.Version,
+				Message: fmt.Sprintf("version %d is recorded as applied more than once", e.Version),
+				Remediation: "check whether the migration ran twice, e.g., from two processes without " +
+					"a lock, and delete the extra row from the version table",
+			})
+		}
+		applied[e.Version] = e.IsApplied
+	}
+	if !hasZero {
+		findings = append(findings, &DoctorFinding{
+			Check:   CheckMissingInitialVersion,
+			Message: "the version table has no version 0 row",
+			Remediation: "insert a row with version_id 0 and is_applied true, which goose writes when " +
+				"it creates the version table",
+		})
+	}
+	for _, e := range history {
+		if e.Version == 0 || e.Source != nil || !applied[e.Version] {
+			continue
+		}
+		// Report each version once, at the row that applied it last.
+		applied[e.Version] = false
+		findings = append(findings, &DoctorFinding{
+			Check:   CheckMissingSource,
+			Version: e.Version,
+			Message: fmt.Sprintf("version %d is applied but has no migration source", e.Version),
+			Remediation: "restore the migration file, or delete the version from the version table " +
+				"if the migration was removed on purpose",
+		})
+	}
+	return findings
+}
+
+// futureHistory returns the rows of the version table recorded later than the current time of the
+// database, or nil if the store cannot compare timestamps with the clock of the database.
+// Timestamps are stored in the session time zone of the database, so they are not compared with
+// the clock of this machine.
+func (p *Provider) futureHistory(ctx context.Context) (_ []*database.HistoryResult, retErr error) {
+	conn, cleanup, err := p.initialize(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize: %w", err)
+	}
+	defer func() {
+		retErr = multierr.Append(retErr, cleanup())
+	}()
+	future, err := p.store.ListFutureHistory(ctx, conn)
+	if errors.Is(err, errors.ErrUnsupported) {
+		return nil, nil
+	}
+	return future, err
+}
+
+// checkFuture reports rows of the version table recorded in the future.
+func checkFuture(future []*database.HistoryResult) []*DoctorFinding {
+	var findings []*DoctorFinding
+	for _, r := range future {
+		findings = append(findings, &DoctorFinding{
+			Check:   CheckFutureTimestamp,
+			Version: r.Version,
+			Message: fmt.Sprintf("version %d was recorded in the future, at %s",
+				r.Version, r.Timestamp.Format(time.DateTime)),
+			Remediation: "check that the clock of the database was not set back, and that nothing " +
+				"writes the tstamp column of the version table with a clock of its own",
+		})
+	}
+	return findings
+}
+
+// migrationTypes returns the type recorded for each applied version, or nil if the store does not
+// record types.
+func (p *Provider) migrationTypes(ctx context.Context) (_ map[int64]string, retErr error) {
+	conn, cleanup, err := p.initialize(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize: %w", err)
+	}
+	defer func() {
+		retErr = multierr.Append(retErr, cleanup())
+	}()
+	types, err := p.store.ListTypes(ctx, conn)
+	if errors.Is(err, errors.ErrUnsupported) {
+		return nil, nil
+	}
+	return types, err
+}
+
+// checkTypes reports migrations whose type differs from the type recorded when they were applied.
+func checkTypes(migrations []*Migration, types map[int64]string) []*DoctorFinding {
+	var findings []*DoctorFinding
+	for _, m := range migrations {
+		recorded, ok := types[m.Version]
+		if !ok || recorded == string(m.Type) {
+			continue
+		}
+		findings = append(findings, &DoctorFinding{
+			Check:   CheckTypeChanged,
+			Version: m.Version,
+			Message: fmt.Sprintf("version %d was applied as a %s migration, but its source is a %s migration",
+				m.Version, recorded, m.Type),
+			Remediation: "an applied migration is not run again, so restore the original, or make sure the " +
+				"new migration matches what was applied and update migration_type in the version table",
+		})
+	}
+	return findings
+}
+
+// checkVersionGaps reports gaps between sequential migrations, which are numbered 1, 2, 3, etc.
+// Timestamped migrations are not expected to be contiguous and are ignored.
+func checkVersionGaps(migrations []*Migration) []*DoctorFinding {
+	var findings []*DoctorFinding
+	var prev int64
+	for _, m := range migrations {
+		if isTimestampVersion(m.Version) {
+			break
+		}
+		if prev > 0 && m.Version > prev+1 {
+			findings = append(findings, &DoctorFinding{
+				Check:   CheckVersionGap,
+				Version: m.Version,
+				Message: fmt.Sprintf("versions %d to %d are missing before version %d",
+					prev+1, m.Version-1, m.Version),
+				Remediation: "restore the missing migrations if they were lost, e.g., in a merge; " +
+					"gaps left by deleted migrations are harmless",
+			})
+		}
+		prev = m.Version
+	}
+	return findings
+}
+
+// isTimestampVersion reports whether version is a timestamp, like the versions of migrations
+// created with the timestamp format.
+func isTimestampVersion(version int64) bool {
+	t, err := time.Parse(timestampFormat, fmt.Sprint(version))
+	return err == nil && t.After(time.Unix(0, 0))
+}
+
+// checkLease reports a table-based lock that is held past the expiry of its lease.
+func checkLease(status *lock.LeaseStatus, now time.Time) *DoctorFinding {
+	if status == nil || !status.Locked || status.LeaseExpiresAt.IsZero() || status.LeaseExpiresAt.After(now) {
+		return nil
+	}
+	return &DoctorFinding{
+		Check: CheckStaleLock,
+		Message: fmt.Sprintf("the lock held by %q has a lease that expired at %s",
+			status.LockedBy, status.LeaseExpiresAt.Format(time.RFC3339)),
+		Remediation: "make sure the process holding the lock is no longer running; the lock is " +
+			"reclaimed the next time goose acquires it",
+	}
+}
@@ -3,6 +3,8 @@ package goose
 import (
 	"errors"
 	"fmt"
+
+	"github.com/pressly/goose/v3/internal/sqlparser"
 )
 
 var (
@@ -46,3 +48,9 @@ func (e *PartialError) Error() string {
 func (e *PartialError) Unwrap() error {
 	return e.Err
 }
+
+// ParseError is returned when a SQL migration file cannot be parsed, for example due to an unknown
+// annotation, a missing StatementEnd annotation or an unterminated statement. It records the
+// migration path, the line and column of the problem, the offending annotation and the parser
+// state. Use [errors.As] to retrieve it from errors returned by the [Provider].
+type ParseError = sqlparser.ParseError
@@ -0,0 +1,289 @@
+package goose
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ErrTargetSkipped is the error of a target that was not migrated because an earlier target failed
+// and [WithStopOnError] is set.
+var ErrTargetSkipped = errors.New("skipped after an earlier target failed")
+
+// Target is a database that a [MultiProvider] applies migrations to.
+//
+// To migrate one Postgres schema per tenant, open a DB per schema with the search_path set for
+// every connection, e.g., by adding search_path=tenant to the connection string of pgx.
+type Target struct {
+	// Name identifies the target in results and errors, e.g., the tenant. Names must be unique.
+	Name string
+	// DB is the database of the target. It is closed by [MultiProvider.Close].
+	DB *sql.DB
+	// Options are applied after the options of [WithProviderOptions], for this target only. For
+	// example, a locker with a lock ID of its own, so targets on the same server do not wait for
+	// each other.
+	Options []ProviderOption
+}
+
+// TargetResult is the outcome of a command for a single [Target].
+type TargetResult struct {
+	Target string
+	// Results are the migrations that were applied or rolled back, including the one that failed,
+	// if any.
+	Results []*MigrationResult
+	// Err is the error of the target, or nil if it succeeded.
+	Err error
+}
+
+// MultiError is returned by [MultiProvider] methods if a command failed for one or more targets.
+type MultiError struct {
+	// Failed are the results of the targets that failed or were skipped, in the order of the
+	// targets.
+	Failed []*TargetResult
+	// Total is the number of targets.
+	Total int
+}
+
+func (e *MultiError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d of %d targets failed:", len(e.Failed), e.Total)
+	for _, r := range e.Failed {
+		fmt.Fprintf(&b, "\n\t%s: %v", r.Target, r.Err)
+	}
+	return b.String()
+}
+
+// MultiProvider applies the same set of migrations to many databases, or many schemas of one
+// database, with a bounded number of targets migrated at once. Each target has a [Provider] of
+// its own, so locking and versioning work per target.
+//
+// A failed target does not stop the others, unless [WithStopOnError] is set. Since applying
+// migrations is idempotent, running a command again resumes the targets that failed or were
+// skipped.
+type MultiProvider struct {
+	targets   []Target
+	providers []*Provider
+	cfg       multiConfig
+}
+
+// NewMultiProvider returns a new MultiProvider that applies the migrations of fsys to the targets.
+// See [NewProvider] for the dialect and fsys, and [MultiProviderOption] for the options.
+//
+// The MultiProvider owns the DB of every target. If it cannot be created, the DBs are closed.
+func NewMultiProvider(dialect Dialect, fsys fs.FS, targets []Target, opts ...MultiProviderOption) (_ *MultiProvider, retErr error) {
+	if len(targets) == 0 {
+		return nil, errors.New("at least one target is required")
+	}
+	providers := make([]*Provider, 0, len(targets))
+	defer func() {
+		if retErr == nil {
+			return
+		}
+		// Close the providers built so far and the DBs of the remaining targets.
+		for _, p := range providers {
+			_ = p.Close()
+		}
+		for _, t := range targets[len(providers):] {
+			if t.DB != nil {
+				_ = t.DB.Close()
+			}
+		}
+	}()
+	cfg := multiConfig{concurrency: 1}
+	for _, opt := range opts {
+		if err := opt.apply(&cfg); err != nil {
+			return nil, err
+		}
+	}
+	names := make(map[string]bool)
+	for _, t := range targets {
+		if t.Name == "" {
+			return nil, errors.New("target name must not be empty")
+		}
+		if names[t.Name] {
+			return nil, fmt.Errorf("duplicate target name: %s", t.Name)
+		}
+		names[t.Name] = true
+		p, err := NewProvider(dialect, t.DB, fsys, slices.Concat(cfg.providerOptions, t.Options)...)
+		if err != nil {
+			return nil, fmt.Errorf("target %s: %w", t.Name, err)
+		}
+		providers = append(providers, p)
+	}
+	return &MultiProvider{
+		targets:   targets,
+		providers: providers,
+		cfg:       cfg,
+	}, nil
+}
+
+// Up applies all pending migrations to every target.
+func (m *MultiProvider) Up(ctx context.Context) ([]*TargetResult, error) {
+	return m.run(ctx, func(ctx context.Context, p *Provider) ([]*MigrationResult, error) {
+		return p.Up(ctx)
+	})
+}
+
+// UpTo applies all pending migrations up to, and including, the specified version to every
+// target.
+func (m *MultiProvider) UpTo(ctx context.Context, version int64) ([]*TargetResult, error) {
+	return m.run(ctx, func(ctx context.Context, p *Provider) ([]*MigrationResult, error) {
+		return p.UpTo(ctx, version)
+	})
+}
+
+// Down rolls back the most recently applied migration of every target. Targets with no migration
+// to roll back succeed without results.
+func (m *MultiProvider) Down(ctx context.Context) ([]*TargetResult, error) {
+	return m.run(ctx, func(ctx context.Context, p *Provider) ([]*MigrationResult, error) {
+		res, err := p.Down(ctx)
+		if err != nil {
+			if errors.Is(err, ErrNoNextVersion) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return []*MigrationResult{res}, nil
+	})
+}
+
+// DownTo rolls back the migrations of every target down to, but not including, the specified
+// version.
+func (m *MultiProvider) DownTo(ctx context.Context, version int64) ([]*TargetResult, error) {
+	return m.run(ctx, func(ctx context.Context, p *Provider) ([]*MigrationResult, error) {
+		return p.DownTo(ctx, version)
+	})
+}
+
+// Close closes the providers of all targets, which closes the DB of every target.
+func (m *MultiProvider) Close() error {
+	var errs []error
+	for i, p := range m.providers {
+		if err := p.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("target %s: %w", m.targets[i].Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *MultiProvider) run(
+	ctx context.Context,
+	fn func(context.Context, *Provider) ([]*MigrationResult, error),
+) ([]*TargetResult, error) {
+	results := make([]*TargetResult, len(m.targets))
+	var (
+		mu     sync.Mutex
+		failed bool
+	)
+	var g errgroup.Group
+	g.SetLimit(m.cfg.concurrency)
+	for i, p := range m.providers {
+		res := &TargetResult{Target: m.targets[i].Name}
+		results[i] = res
+		g.Go(func() error {
+			mu.Lock()
+			skip := failed && m.cfg.stopOnError
+			mu.Unlock()
+			switch {
+			case skip:
+				res.Err = ErrTargetSkipped
+			case ctx.Err() != nil:
+				res.Err = ctx.Err()
+			default:
+				res.Results, res.Err = fn(ctx, p)
+				var partialErr *PartialError
+				if errors.As(res.Err, &partialErr) && partialErr.Failed != nil {
+					res.Results = slices.Concat(partialErr.Applied, []*MigrationResult{partialErr.Failed})
+				}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if res.Err != nil {
+				failed = true
+			}
+			if m.cfg.onTarget != nil {
+				m.cfg.onTarget(res)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	var multiErr MultiError
+	for _, res := range results {
+		if res.Err != nil {
+			multiErr.Failed = append(multiErr.Failed, res)
+		}
+	}
+	if len(multiErr.Failed) > 0 {
+		multiErr.Total = len(results)
+		return results, &multiErr
+	}
+	return results, nil
+}
+
+// MultiProviderOption is a configuration option for a [MultiProvider].
+type MultiProviderOption interface {
+	apply(*multiConfig) error
+}
+
+type multiConfig struct {
+	concurrency     int
+	stopOnError     bool
+	providerOptions []ProviderOption
+	onTarget        func(*TargetResult)
+}
+
+type multiConfigFunc func(*multiConfig) error
+
+func (f multiConfigFunc) apply(cfg *multiConfig) error {
+	return f(cfg)
+}
+
+// WithConcurrency sets how many targets are migrated at once. Default is 1, one target at a time.
+func WithConcurrency(n int) MultiProviderOption {
+	return multiConfigFunc(func(c *multiConfig) error {
+		if n < 1 {
+			return fmt.Errorf("invalid concurrency %d: must be at least 1", n)
+		}
+		c.concurrency = n
+		return nil
+	})
+}
+
+// WithStopOnError stops starting new targets once a target failed. Targets that were not started
+// fail with [ErrTargetSkipped]. By default, every target is migrated regardless of failures.
+func WithStopOnError(b bool) MultiProviderOption {
+	return multiConfigFunc(func(c *multiConfig) error {
+		c.stopOnError = b
+		return nil
+	})
+}
+
+// WithProviderOptions sets the options of the [Provider] of every target. See [Target] for options
+// of a single target. Lockers should be set per target, since a table-based locker is held by one
+// provider at a time.
+func WithProviderOptions(opts ...ProviderOption) MultiProviderOption {
+	return multiConfigFunc(func(c *multiConfig) error {
+		c.providerOptions = append(c.providerOptions, opts...)
+		return nil
+	})
+}
+
+// WithTargetDone sets a function that is called when a target finished, e.g., to report progress.
+// Calls are not concurrent, but targets may finish in any order.
+func WithTargetDone(fn func(*TargetResult)) MultiProviderOption {
+	return multiConfigFunc(func(c *multiConfig) error {
+		if fn == nil {
+			return errors.New("target done function must not be nil")
+		}
+		c.onTarget = fn
+		return nil
+	})
+}
@@ -0,0 +1,128 @@
+package goose_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiProvider(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	newTargets := func(t *testing.T, names ...string) []goose.Target {
+		t.Helper()
+		targets := make([]goose.Target, 0, len(names))
+		for _, name := range names {
+			targets = append(targets, goose.Target{Name: name, DB: newDB(t)})
+		}
+		return targets
+	}
+	t.Run("up_and_down", func(t *testing.T) {
+		var (
+			mu   sync.Mutex
+			done []string
+		)
+		mp, err := goose.NewMultiProvider(goose.DialectSQLite3, newFsys(), newTargets(t, "a", "b", "c"),
+			goose.WithConcurrency(2),
+			goose.WithTargetDone(func(res *goose.TargetResult) {
+				mu.Lock()
+				defer mu.Unlock()
+				done = append(done, res.Target)
+			}),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, mp.Close()) })
+		results, err := mp.UpTo(ctx, 3)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		for i, name := range []string{"a", "b", "c"} {
+			require.Equal(t, name, results[i].Target)
+			require.NoError(t, results[i].Err)
+			require.Len(t, results[i].Results, 3)
+		}
+		require.ElementsMatch(t, []string{"a", "b", "c"}, done)
+		// Running again is a no-op for every target.
+		results, err = mp.UpTo(ctx, 3)
+		require.NoError(t, err)
+		for _, res := range results {
+			require.Empty(t, res.Results)
+		}
+		results, err = mp.DownTo(ctx, 0)
+		require.NoError(t, err)
+		for _, res := range results {
+			require.Len(t, res.Results, 3)
+		}
+		// Nothing left to roll back.
+		results, err = mp.Down(ctx)
+		require.NoError(t, err)
+		for _, res := range results {
+			require.Empty(t, res.Results)
+		}
+	})
+	t.Run("failed_target", func(t *testing.T) {
+		targets := newTargets(t, "a", "b", "c")
+		// The users table of the first migration already exists in b.
+		_, err := targets[1].DB.ExecContext(ctx, "CREATE TABLE users (id INTEGER)")
+		require.NoError(t, err)
+		mp, err := goose.NewMultiProvider(goose.DialectSQLite3, newFsys(), targets)
+		require.NoError(t, err)
+		results, err := mp.Up(ctx)
+		var multiErr *goose.MultiError
+		require.True(t, errors.As(err, &multiErr))
+		require.Equal(t, 3, multiErr.Total)
+		require.Len(t, multiErr.Failed, 1)
+		require.Equal(t, "b", multiErr.Failed[0].Target)
+		require.Len(t, results[1].Results, 1)
+		require.Error(t, results[1].Results[0].Error)
+		require.NoError(t, results[0].Err)
+		require.NoError(t, results[2].Err)
+
+		// Once fixed, running again migrates only the failed target.
+		_, err = targets[1].DB.ExecContext(ctx, "DROP TABLE users")
+		require.NoError(t, err)
+		results, err = mp.Up(ctx)
+		require.NoError(t, err)
+		require.Empty(t, results[0].Results)
+		require.NotEmpty(t, results[1].Results)
+		require.Empty(t, results[2].Results)
+	})
+	t.Run("stop_on_error", func(t *testing.T) {
+		targets := newTargets(t, "a", "b", "c")
+		_, err := targets[0].DB.ExecContext(ctx, "CREATE TABLE users (id INTEGER)")
+		require.NoError(t, err)
+		mp, err := goose.NewMultiProvider(goose.DialectSQLite3, newFsys(), targets,
+			goose.WithStopOnError(true),
+		)
+		require.NoError(t, err)
+		results, err := mp.Up(ctx)
+		require.Error(t, err)
+		require.Error(t, results[0].Err)
+		require.ErrorIs(t, results[1].Err, goose.ErrTargetSkipped)
+		require.ErrorIs(t, results[2].Err, goose.ErrTargetSkipped)
+	})
+	t.Run("closes_on_error", func(t *testing.T) {
+		targets := newTargets(t, "a", "b", "c")
+		targets[1].Options = []goose.ProviderOption{goose.WithStore(nil)}
+		_, err := goose.NewMultiProvider(goose.DialectSQLite3, newFsys(), targets)
+		require.Error(t, err)
+		// The DBs of the target built before the failure and of the remaining targets are closed.
+		for _, target := range targets {
+			require.ErrorContains(t, target.DB.PingContext(ctx), "database is closed")
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := goose.NewMultiProvider(goose.DialectSQLite3, newFsys(), nil)
+		require.Error(t, err)
+		_, err = goose.NewMultiProvider(goose.DialectSQLite3, newFsys(), newTargets(t, "a", "a"))
+		require.Error(t, err)
+		_, err = goose.NewMultiProvider(goose.DialectSQLite3, newFsys(), newTargets(t, ""))
+		require.Error(t, err)
+		_, err = goose.NewMultiProvider(goose.DialectSQLite3, newFsys(), newTargets(t, "a"), goose.WithConcurrency(0))
+		require.Error(t, err)
+	})
+}
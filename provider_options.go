@@ -1,11 +1,14 @@
 package goose
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 
 	"github.com/pressly/goose/v3/database"
+	"github.com/pressly/goose/v3/internal/sqlparser"
 	"github.com/pressly/goose/v3/lock"
 )
 
@@ -45,6 +48,71 @@ func WithStore(store database.Store) ProviderOption {
 	})
 }
 
+// WithStoreDB keeps the version table in db, a different database from the one migrations run
+// against, together with the lock of [WithLocker] or [WithSessionLocker]. This is useful when the
+// migrated database has no transactions, such as ClickHouse or StarRocks, and the migration history
+// is more reliable in a small Postgres database.
+//
+// dialect is the dialect of db and selects the store, while the dialect passed to [NewProvider]
+// still selects how SQL migrations are parsed. To use a custom store in db, set dialect to
+// [DialectCustom] and use [WithStore].
+//
+// A migration and its version cannot be committed in the same transaction. The version is recorded
+// after the migration succeeded, the same as for migrations that run without a transaction. db is
+// not closed by [Provider.Close].
+func WithStoreDB(dialect Dialect, db *sql.DB) ProviderOption {
+	return configFunc(func(c *config) error {
+		if c.storeDB != nil {
+			return errors.New("store db already set")
+		}
+		if db == nil {
+			return errors.New("store db must not be nil")
+		}
+		c.storeDB = db
+		c.storeDialect = dialect
+		return nil
+	})
+}
+
+// WithClickHouse configures the ClickHouse dialect for a cluster. If opts.Cluster is set, the
+// version table is created ON CLUSTER with a ReplicatedMergeTree engine, and opts.Final and
+// opts.SequentialConsistency make sure every node reads the same migration history.
+//
+// SQL migrations of the ClickHouse dialect can use the GOOSE_CLUSTER variable, the cluster name,
+// and GOOSE_ON_CLUSTER, an ON CLUSTER clause or an empty string without a cluster, with the ENVSUB
+// annotation:
+//
+//	-- +goose ENVSUB ON
+//	-- +goose Up
+//	CREATE TABLE events ${GOOSE_ON_CLUSTER} (id UInt64) ENGINE = ReplicatedMergeTree ORDER BY id;
+//
+// This option requires [DialectClickHouse], either as the dialect of [NewProvider] or of
+// [WithStoreDB].
+func WithClickHouse(opts database.ClickHouseOptions) ProviderOption {
+	return configFunc(func(c *config) error {
+		if c.clickhouse != nil {
+			return errors.New("clickhouse options already set")
+		}
+		c.clickhouse = &opts
+		return nil
+	})
+}
+
+// WithCreateSchema creates the schema of a schema-qualified version table, such as
+// "migrations.goose_db_version" set with [WithTableName], if it does not exist, right before the
+// version table is created. owner is the owner of a new schema, or empty for the current user.
+//
+// This option is supported by the Postgres, Redshift, Aurora DSQL and CockroachDB dialects, and
+// cannot be used together with [WithStore]. To create the schema of a lock table, use
+// [lock.WithTableCreateSchema].
+func WithCreateSchema(owner string) ProviderOption {
+	return configFunc(func(c *config) error {
+		c.createSchema = true
+		c.schemaOwner = owner
+		return nil
+	})
+}
+
 // WithTableName sets the name of the database table used to track history of applied migrations.
 // This option cannot be used together with [WithStore], since the table name is set on the store.
 //
@@ -146,6 +214,32 @@ func WithExcludeVersions(versions []int64) ProviderOption {
 	})
 }
 
+// WithAdditionalFS adds a filesystem of migration files, which are merged with those of the
+// filesystem passed to [NewProvider] into a single set ordered by version. This allows sharing a
+// library of migrations, such as audit tables or common functions, between services. A version
+// must be unique across all filesystems.
+//
+// The sources of the additional filesystem are reported with name as a path prefix, e.g.,
+// "shared/00001_audit.sql" for the name "shared". name must be a valid, unrooted, slash-separated
+// path as defined by [fs.ValidPath], other than ".", and must be unique.
+func WithAdditionalFS(name string, fsys fs.FS) ProviderOption {
+	return configFunc(func(c *config) error {
+		if name == "." || !fs.ValidPath(name) {
+			return fmt.Errorf("invalid additional filesystem name %q", name)
+		}
+		if fsys == nil {
+			return errors.New("additional filesystem must not be nil")
+		}
+		for _, f := range c.additionalFS {
+			if f.name == name {
+				return fmt.Errorf("duplicate additional filesystem name: %s", name)
+			}
+		}
+		c.additionalFS = append(c.additionalFS, namedFS{name: name, fsys: fsys})
+		return nil
+	})
+}
+
 // WithGoMigrations registers Go migrations with the provider. If a Go migration with the same
 // version has already been registered, an error will be returned.
 //
@@ -244,13 +338,39 @@ func WithIsolateDDL(b bool) ProviderOption {
 	})
 }
 
+// WithMigrationDone sets a function that is called every time a migration finished, including a
+// migration that failed, whose result has the error set. It is called before the next migration
+// runs and while the lock is held, so it can report the progress of long runs. Calls are not
+// concurrent.
+func WithMigrationDone(fn func(*MigrationResult)) ProviderOption {
+	return configFunc(func(c *config) error {
+		if fn == nil {
+			return errors.New("migration done function must not be nil")
+		}
+		c.onMigration = fn
+		return nil
+	})
+}
+
 type config struct {
 	tableName string
 	store     database.Store
 
+	// storeDB and storeDialect are set by WithStoreDB.
+	storeDB      *sql.DB
+	storeDialect Dialect
+
+	// createSchema and schemaOwner are set by WithCreateSchema.
+	createSchema bool
+	schemaOwner  string
+
+	// clickhouse is set by WithClickHouse.
+	clickhouse *database.ClickHouseOptions
+
 	verbose         bool
 	excludePaths    map[string]bool
 	excludeVersions map[int64]bool
+	additionalFS    []namedFS
 
 	// Go migrations registered by the user. These will be merged/resolved against the globally
 	// registered migrations.
@@ -267,6 +387,15 @@ type config struct {
 	disableGlobalRegistry bool
 	isolateDDL            bool
 
+	// onMigration is set by WithMigrationDone.
+	onMigration func(*MigrationResult)
+
+	// sqlParseOptions are the options to parse SQL migrations with, set from the dialect.
+	sqlParseOptions []sqlparser.ParseOption
+	// retryTx retries migration transactions that fail with a transaction restart error, set
+	// for the cockroachdb dialect.
+	retryTx bool
+
 	// Only a single logger can be set, they are mutually exclusive. If neither is set, a default
 	// [Logger] will be set to maintain backward compatibility in /v3.
 	logger  Logger
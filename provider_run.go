@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/pressly/goose/v3/database"
+	"github.com/pressly/goose/v3/internal/cockroachdb"
 	"github.com/pressly/goose/v3/internal/sqlparser"
 	"github.com/sethvargo/go-retry"
 	"go.uber.org/multierr"
@@ -47,7 +48,7 @@ func (p *Provider) prepareMigration(fsys fs.FS, m *Migration, direction bool) er
 		// use *sql.DB and document that the user SHOULD NOT SET max open connections to 1. This is
 		// a bit of an edge case. For now, we guard against this scenario by checking the max open
 		// connections and returning an error.
-		if p.cfg.lockEnabled && p.cfg.sessionLocker != nil && p.db.Stats().MaxOpenConnections == 1 {
+		if p.cfg.lockEnabled && p.cfg.sessionLocker != nil && p.storeDB == p.db && p.db.Stats().MaxOpenConnections == 1 {
 			if !useTx {
 				return errors.New("potential deadlock detected: cannot run Go migration without a transaction when max open connections set to 1")
 			}
@@ -57,7 +58,7 @@ func (p *Provider) prepareMigration(fsys fs.FS, m *Migration, direction bool) er
 		if m.sql.Parsed {
 			return nil
 		}
-		parsed, err := sqlparser.ParseAllFromFS(fsys, m.Source, false)
+		parsed, err := sqlparser.ParseAllFromFS(fsys, m.Source, false, p.cfg.sqlParseOptions...)
 		if err != nil {
 			return err
 		}
@@ -160,6 +161,9 @@ func (p *Provider) runMigrations(
 			// the apply slice.
 			result.Error = err
 			result.Duration = time.Since(start)
+			if p.cfg.onMigration != nil {
+				p.cfg.onMigration(result)
+			}
 			return nil, &PartialError{
 				Applied: results,
 				Failed:  result,
@@ -185,6 +189,9 @@ func (p *Provider) runMigrations(
 			slog.Int64("version", result.Source.Version),
 			slog.String("type", string(result.Source.Type)),
 		)
+		if p.cfg.onMigration != nil {
+			p.cfg.onMigration(result)
+		}
 	}
 	if !p.cfg.disableVersioning && !byOne {
 		maxVersion, err := p.getDBMaxVersion(ctx, conn)
@@ -210,12 +217,22 @@ func (p *Provider) runIndividually(
 	if err != nil {
 		return err
 	}
+	if p.storeDB != p.db {
+		// The version table is in another database, so conn is a connection to that database and
+		// the migration runs on a connection of its own.
+		if err := p.runOnDB(ctx, m, direction, useTx && !p.cfg.isolateDDL); err != nil {
+			return err
+		}
+		return p.maybeInsertOrDelete(ctx, conn, m, direction)
+	}
 	if useTx && !p.cfg.isolateDDL {
-		return beginTx(ctx, conn, func(tx *sql.Tx) error {
-			if err := p.runMigration(ctx, tx, m, direction); err != nil {
-				return err
-			}
-			return p.maybeInsertOrDelete(ctx, tx, m.Version, direction)
+		return p.retryTx(ctx, func(ctx context.Context) error {
+			return beginTx(ctx, conn, func(tx *sql.Tx) error {
+				if err := p.runMigration(ctx, tx, m, direction); err != nil {
+					return err
+				}
+				return p.maybeInsertOrDelete(ctx, tx, m, direction)
+			})
 		})
 	}
 	switch m.Type {
@@ -230,20 +247,44 @@ func (p *Provider) runIndividually(
 		if err := p.runMigration(ctx, p.db, m, direction); err != nil {
 			return err
 		}
-		return p.maybeInsertOrDelete(ctx, p.db, m.Version, direction)
+		return p.maybeInsertOrDelete(ctx, p.db, m, direction)
 	case TypeSQL:
 		if err := p.runMigration(ctx, conn, m, direction); err != nil {
 			return err
 		}
-		return p.maybeInsertOrDelete(ctx, conn, m.Version, direction)
+		return p.maybeInsertOrDelete(ctx, conn, m, direction)
 	}
 	return fmt.Errorf("failed to run individual migration: neither sql or go: %v", m)
 }
 
+// runOnDB runs a migration against the database of the provider when the version table is kept in
+// another database, see [WithStoreDB].
+func (p *Provider) runOnDB(ctx context.Context, m *Migration, direction, useTx bool) (retErr error) {
+	if m.Type == TypeGo && !useTx {
+		// Go migrations without a transaction run on *sql.DB, see runIndividually.
+		return p.runMigration(ctx, p.db, m, direction)
+	}
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		retErr = multierr.Append(retErr, conn.Close())
+	}()
+	if useTx {
+		return p.retryTx(ctx, func(ctx context.Context) error {
+			return beginTx(ctx, conn, func(tx *sql.Tx) error {
+				return p.runMigration(ctx, tx, m, direction)
+			})
+		})
+	}
+	return p.runMigration(ctx, conn, m, direction)
+}
+
 func (p *Provider) maybeInsertOrDelete(
 	ctx context.Context,
 	db database.DBTxConn,
-	version int64,
+	m *Migration,
 	direction bool,
 ) error {
 	// If versioning is disabled, we don't need to insert or delete the migration version.
@@ -251,9 +292,19 @@ func (p *Provider) maybeInsertOrDelete(
 		return nil
 	}
 	if direction {
-		return p.store.Insert(ctx, db, database.InsertRequest{Version: version})
+		return p.store.Insert(ctx, db, database.InsertRequest{Version: m.Version, Type: string(m.Type)})
+	}
+	return p.store.Delete(ctx, db, m.Version)
+}
+
+// retryTx runs fn, which runs a migration in a transaction of its own. On CockroachDB, the whole
+// transaction is run again if it is aborted with a transaction restart error (SQLSTATE 40001), so
+// the migration and the version table write are retried together.
+func (p *Provider) retryTx(ctx context.Context, fn func(context.Context) error) error {
+	if !p.cfg.retryTx {
+		return fn(ctx)
 	}
-	return p.store.Delete(ctx, db, version)
+	return cockroachdb.Retry(ctx, fn)
 }
 
 // beginTx begins a transaction and runs the given function. If the function returns an error, the
@@ -276,7 +327,9 @@ func beginTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) (re
 
 func (p *Provider) initialize(ctx context.Context, useLocker bool) (*sql.Conn, func() error, error) {
 	p.mu.Lock()
-	conn, err := p.db.Conn(ctx)
+	// The connection is used for the version table and the lock. It is also used to run migrations,
+	// unless the version table is kept in another database.
+	conn, err := p.storeDB.Conn(ctx)
 	if err != nil {
 		p.mu.Unlock()
 		return nil, nil, err
@@ -310,7 +363,7 @@ func (p *Provider) initialize(ctx context.Context, useLocker bool) (*sql.Conn, f
 		// General locker (db-based locking)
 		if p.cfg.locker != nil {
 			l := p.cfg.locker
-			if err := l.Lock(ctx, p.db); err != nil {
+			if err := l.Lock(ctx, p.storeDB); err != nil {
 				return nil, nil, multierr.Append(err, cleanup())
 			}
 			// A lock was acquired, so we need to unlock when we're done.
@@ -319,7 +372,7 @@ func (p *Provider) initialize(ctx context.Context, useLocker bool) (*sql.Conn, f
 				// Use a detached context to unlock. This is because the context passed to Lock may
 				// have been canceled, and we don't want to cancel the unlock.
 				return multierr.Append(
-					l.Unlock(context.WithoutCancel(ctx), p.db),
+					l.Unlock(context.WithoutCancel(ctx), p.storeDB),
 					conn.Close(),
 				)
 			}
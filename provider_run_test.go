@@ -155,6 +155,55 @@ func TestProviderRun(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, upToVersion, gotVersion)
 	})
+	t.Run("redo", func(t *testing.T) {
+		ctx := context.Background()
+		locker := &countingLocker{}
+		p, _ := newProviderWithDB(t, goose.WithLocker(locker))
+		_, err := p.Redo(ctx)
+		require.ErrorIs(t, err, goose.ErrNoNextVersion)
+		_, err = p.UpTo(ctx, 2)
+		require.NoError(t, err)
+		locker.locks = 0
+		results, err := p.Redo(ctx)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assertResult(t, results[0], newSource(goose.TypeSQL, "00002_posts_table.sql", 2), "down", false)
+		assertResult(t, results[1], newSource(goose.TypeSQL, "00002_posts_table.sql", 2), "up", false)
+		// Both steps run under a single lock.
+		require.Equal(t, 1, locker.locks)
+		currentVersion, err := p.GetDBVersion(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, currentVersion)
+	})
+	t.Run("migration_done", func(t *testing.T) {
+		ctx := context.Background()
+		var done []*goose.MigrationResult
+		p, _ := newProviderWithDB(t, goose.WithMigrationDone(func(res *goose.MigrationResult) {
+			done = append(done, res)
+		}))
+		results, err := p.UpTo(ctx, 3)
+		require.NoError(t, err)
+		require.Equal(t, results, done)
+
+		done = nil
+		p, err = goose.NewProvider(goose.DialectSQLite3, newDB(t), fstest.MapFS{
+			"00001_a.sql": newMapFile("-- +goose Up\nCREATE TABLE a (id INTEGER);\n"),
+			"00002_b.sql": newMapFile("-- +goose Up\nINSERT INTO invalid_table VALUES (1);\n"),
+		}, goose.WithMigrationDone(func(res *goose.MigrationResult) {
+			done = append(done, res)
+		}))
+		require.NoError(t, err)
+		_, err = p.Up(ctx)
+		var partialErr *goose.PartialError
+		require.ErrorAs(t, err, &partialErr)
+		// The failed migration is reported as well.
+		require.Len(t, done, 2)
+		require.NoError(t, done[0].Error)
+		require.Equal(t, partialErr.Failed, done[1])
+
+		_, err = goose.NewProvider(goose.DialectSQLite3, newDB(t), newFsys(), goose.WithMigrationDone(nil))
+		require.Error(t, err)
+	})
 	t.Run("sql_connections", func(t *testing.T) {
 		tt := []struct {
 			name         string
@@ -776,6 +825,138 @@ func TestCustomStoreTableExists(t *testing.T) {
 	}
 }
 
+func TestProviderHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	check := func(t *testing.T, p *goose.Provider, db *sql.DB) {
+		t.Helper()
+		_, err := p.UpTo(ctx, 2)
+		require.NoError(t, err)
+		_, err = p.Down(ctx)
+		require.NoError(t, err)
+		// The package-level down functions of older releases recorded rollbacks as rows.
+		_, err = db.ExecContext(ctx, "INSERT INTO goose_db_version (version_id, is_applied) VALUES (1, false)")
+		require.NoError(t, err)
+
+		history, err := p.History(ctx)
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+		for i, want := range []struct {
+			version int64
+			applied bool
+		}{{0, true}, {1, true}, {1, false}} {
+			require.Equal(t, want.version, history[i].Version)
+			require.Equal(t, want.applied, history[i].IsApplied)
+			require.False(t, history[i].Timestamp.IsZero())
+		}
+		require.Nil(t, history[0].Source)
+		assertSource(t, history[1].Source, goose.TypeSQL, "00001_users_table.sql", 1)
+	}
+	t.Run("store", func(t *testing.T) {
+		p, db := newProviderWithDB(t)
+		check(t, p, db)
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		// A custom store that does not implement database.HistoryLister.
+		db := newDB(t)
+		store, err := database.NewStore(database.DialectSQLite3, goose.DefaultTablename)
+		require.NoError(t, err)
+		p, err := goose.NewProvider(goose.DialectCustom, db, newFsys(),
+			goose.WithStore(&customStoreSQLite3{store}),
+		)
+		require.NoError(t, err)
+		_, err = p.History(ctx)
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+	t.Run("no_versioning", func(t *testing.T) {
+		p, _ := newProviderWithDB(t, goose.WithDisableVersioning(true))
+		_, err := p.History(ctx)
+		require.Error(t, err)
+	})
+}
+
+func TestProviderDoctor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	t.Run("healthy", func(t *testing.T) {
+		p, _ := newProviderWithDB(t)
+		_, err := p.Up(ctx)
+		require.NoError(t, err)
+		findings, err := p.Doctor(ctx)
+		require.NoError(t, err)
+		require.Empty(t, findings)
+	})
+	t.Run("findings", func(t *testing.T) {
+		fsys := newFsys()
+		delete(fsys, "00003_comments_table.sql")
+		db := newDB(t)
+		p, err := goose.NewProvider(goose.DialectSQLite3, db, fsys)
+		require.NoError(t, err)
+		_, err = p.UpTo(ctx, 2)
+		require.NoError(t, err)
+		for _, q := range []string{
+			"DELETE FROM goose_db_version WHERE version_id = 0",
+			"INSERT INTO goose_db_version (version_id, is_applied) VALUES (2, true)",
+			"INSERT INTO goose_db_version (version_id, is_applied) VALUES (99, true)",
+			"INSERT INTO goose_db_version (version_id, is_applied, tstamp) VALUES (1, false, '2999-01-01 00:00:00')",
+		} {
+			_, err := db.ExecContext(ctx, q)
+			require.NoError(t, err)
+		}
+		findings, err := p.Doctor(ctx)
+		require.NoError(t, err)
+		got := make(map[goose.DoctorCheck]int64)
+		for _, f := range findings {
+			require.NotEmpty(t, f.Message)
+			require.NotEmpty(t, f.Remediation)
+			got[f.Check] = f.Version
+		}
+		require.Equal(t, map[goose.DoctorCheck]int64{
+			goose.CheckDuplicateVersion:      2,
+			goose.CheckMissingInitialVersion: 0,
+			goose.CheckFutureTimestamp:       1,
+			goose.CheckMissingSource:         99,
+			goose.CheckVersionGap:            4,
+		}, got)
+		require.Len(t, findings, 5)
+	})
+	t.Run("type_changed", func(t *testing.T) {
+		p, db := newProviderWithDB(t)
+		_, err := p.UpTo(ctx, 2)
+		require.NoError(t, err)
+		// Version 1 was applied as a Go migration and later rewritten in SQL.
+		_, err = db.ExecContext(ctx, "UPDATE goose_db_version SET migration_type = 'go' WHERE version_id = 1")
+		require.NoError(t, err)
+		findings, err := p.Doctor(ctx)
+		require.NoError(t, err)
+		require.Len(t, findings, 1)
+		require.Equal(t, goose.CheckTypeChanged, findings[0].Check)
+		require.EqualValues(t, 1, findings[0].Version)
+		require.Contains(t, findings[0].Message, "applied as a go migration, but its source is a sql migration")
+	})
+	t.Run("future_timestamp", func(t *testing.T) {
+		p, db := newProviderWithDB(t)
+		_, err := p.UpTo(ctx, 2)
+		require.NoError(t, err)
+		// A row a few minutes ahead of the clock of the database is reported, whatever the time
+		// zone of this machine.
+		_, err = db.ExecContext(ctx, "UPDATE goose_db_version SET tstamp = datetime('now', '+5 minutes') WHERE version_id = 2")
+		require.NoError(t, err)
+		findings, err := p.Doctor(ctx)
+		require.NoError(t, err)
+		require.Len(t, findings, 1)
+		require.Equal(t, goose.CheckFutureTimestamp, findings[0].Check)
+		require.EqualValues(t, 2, findings[0].Version)
+	})
+	t.Run("no_versioning", func(t *testing.T) {
+		p, _ := newProviderWithDB(t, goose.WithDisableVersioning(true))
+		_, err := p.Doctor(ctx)
+		require.Error(t, err)
+	})
+}
+
 func TestProviderApply(t *testing.T) {
 	t.Parallel()
 
@@ -1041,6 +1222,18 @@ func randomAlphaNumeric(length int) string {
 	return string(b)
 }
 
+// countingLocker is a [lock.Locker] that counts how often it was locked.
+type countingLocker struct {
+	locks int
+}
+
+func (l *countingLocker) Lock(context.Context, *sql.DB) error {
+	l.locks++
+	return nil
+}
+
+func (l *countingLocker) Unlock(context.Context, *sql.DB) error { return nil }
+
 func newProviderWithDB(t *testing.T, opts ...goose.ProviderOption) (*goose.Provider, *sql.DB) {
 	t.Helper()
 	db := newDB(t)
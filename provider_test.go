@@ -1,6 +1,7 @@
 package goose_test
 
 import (
+	"context"
 	"database/sql"
 	"io/fs"
 	"path/filepath"
@@ -8,6 +9,7 @@ import (
 	"testing/fstest"
 
 	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
 	"github.com/stretchr/testify/require"
 	_ "modernc.org/sqlite"
 )
@@ -75,6 +77,309 @@ ALTER TABLE my_foo RENAME TO foo;
 `
 )
 
+func TestProviderRegisteredDialect(t *testing.T) {
+	t.Parallel()
+
+	querier, ok := database.LookupDialect(database.DialectSQLite3)
+	require.True(t, ok)
+	const dialect goose.Dialect = "sqlite3-provider"
+	database.RegisterDialect(dialect, querier)
+	goose.RegisterDriver("sqlite-provider", dialect, "sqlite")
+	d, sqlDriver, ok := goose.LookupDriver("sqlite-provider")
+	require.True(t, ok)
+	require.Equal(t, dialect, d)
+	require.Equal(t, "sqlite", sqlDriver)
+	require.Panics(t, func() { goose.RegisterDriver("sqlite-provider", dialect, "sqlite") })
+	_, _, ok = goose.LookupDriver("unknown")
+	require.False(t, ok)
+
+	db, err := sql.Open(sqlDriver, filepath.Join(t.TempDir(), "registered.db"))
+	require.NoError(t, err)
+	p, err := goose.NewProvider(d, db, fstest.MapFS{
+		"001_foo.sql": {Data: []byte(migration1)},
+	})
+	require.NoError(t, err)
+	results, err := p.Up(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+}
+
+func TestProviderAdditionalFS(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "sql_embed.db"))
+	require.NoError(t, err)
+	fsys := fstest.MapFS{
+		"1_foo.sql": {Data: []byte(migration1)},
+		"3_baz.sql": {Data: []byte(migration3)},
+	}
+	shared := fstest.MapFS{
+		"2_bar.sql": {Data: []byte(migration2)},
+		"4_qux.sql": {Data: []byte(migration4)},
+	}
+	t.Run("merged", func(t *testing.T) {
+		p, err := goose.NewProvider(goose.DialectSQLite3, db, fsys, goose.WithAdditionalFS("shared", shared))
+		require.NoError(t, err)
+		sources := p.ListSources()
+		require.Len(t, sources, 4)
+		require.Equal(t, sources[0], newSource(goose.TypeSQL, "1_foo.sql", 1))
+		require.Equal(t, sources[1], newSource(goose.TypeSQL, "shared/2_bar.sql", 2))
+		require.Equal(t, sources[2], newSource(goose.TypeSQL, "3_baz.sql", 3))
+		require.Equal(t, sources[3], newSource(goose.TypeSQL, "shared/4_qux.sql", 4))
+		results, err := p.Up(t.Context())
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+	})
+	t.Run("duplicate_version", func(t *testing.T) {
+		_, err := goose.NewProvider(goose.DialectSQLite3, db, fsys,
+			goose.WithAdditionalFS("shared", shared),
+			goose.WithAdditionalFS("other", fstest.MapFS{"3_other.sql": {Data: []byte(migration3)}}),
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "found duplicate migration version 3")
+		require.Contains(t, err.Error(), "other/3_other.sql")
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, name := range []string{"", ".", "/abs", "../up", "a/"} {
+			_, err := goose.NewProvider(goose.DialectSQLite3, db, fsys, goose.WithAdditionalFS(name, shared))
+			require.Error(t, err, name)
+		}
+		_, err := goose.NewProvider(goose.DialectSQLite3, db, fsys, goose.WithAdditionalFS("shared", nil))
+		require.Error(t, err)
+		_, err = goose.NewProvider(goose.DialectSQLite3, db, fsys,
+			goose.WithAdditionalFS("shared", shared),
+			goose.WithAdditionalFS("shared", shared),
+		)
+		require.Error(t, err)
+	})
+}
+
+func TestProviderStoreDB(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "migrated.db"))
+	require.NoError(t, err)
+	storeDB, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "store.db"))
+	require.NoError(t, err)
+	fsys := fstest.MapFS{
+		"1_foo.sql": {Data: []byte(migration1)},
+		"2_bar.sql": {Data: []byte(migration2)},
+	}
+	tableExists := func(t *testing.T, db *sql.DB, name string) bool {
+		t.Helper()
+		var n int
+		err := db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type='table' AND name=?`, name).Scan(&n)
+		require.NoError(t, err)
+		return n == 1
+	}
+	p, err := goose.NewProvider(goose.DialectSQLite3, db, fsys, goose.WithStoreDB(goose.DialectSQLite3, storeDB))
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, p.Ping(ctx))
+	results, err := p.Up(ctx)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	// Migrations run against db, the version table is kept in storeDB.
+	require.True(t, tableExists(t, db, "foo"))
+	require.False(t, tableExists(t, db, goose.DefaultTablename))
+	require.False(t, tableExists(t, storeDB, "foo"))
+	require.True(t, tableExists(t, storeDB, goose.DefaultTablename))
+	version, err := p.GetDBVersion(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, version)
+	_, err = p.Down(ctx)
+	require.NoError(t, err)
+	version, err = p.GetDBVersion(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, version)
+	// The store database stays open.
+	require.NoError(t, p.Close())
+	require.NoError(t, storeDB.Ping())
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := goose.NewProvider(goose.DialectSQLite3, db, fsys, goose.WithStoreDB(goose.DialectSQLite3, nil))
+		require.Error(t, err)
+		_, err = goose.NewProvider(goose.DialectSQLite3, db, fsys, goose.WithStoreDB(goose.DialectCustom, storeDB))
+		require.Error(t, err)
+		_, err = goose.NewProvider(goose.DialectSQLite3, db, fsys,
+			goose.WithStoreDB(goose.DialectSQLite3, storeDB),
+			goose.WithStoreDB(goose.DialectSQLite3, storeDB),
+		)
+		require.Error(t, err)
+	})
+}
+
+func TestProviderCockroachDBRetry(t *testing.T) {
+	t.Parallel()
+
+	// The Go migration runs in a transaction on SQLite, which is enough to check that the
+	// cockroachdb dialect runs the whole transaction again after a restart error.
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "migrated.db"))
+	require.NoError(t, err)
+	storeDB, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "store.db"))
+	require.NoError(t, err)
+	var calls int
+	up := &goose.GoFunc{RunTx: func(ctx context.Context, tx *sql.Tx) error {
+		calls++
+		if _, err := tx.ExecContext(ctx, "CREATE TABLE users (id INTEGER)"); err != nil {
+			return err
+		}
+		if calls < 3 {
+			return sqlStateError("40001")
+		}
+		return nil
+	}}
+	p, err := goose.NewProvider(goose.DialectCockroachDB, db, nil,
+		goose.WithStoreDB(goose.DialectSQLite3, storeDB),
+		goose.WithGoMigrations(goose.NewGoMigration(1, up, nil)),
+		goose.WithDisableGlobalRegistry(true),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+	_, err = p.Up(ctx)
+	require.NoError(t, err)
+	// The failed attempts were rolled back, so the table is created again each time.
+	require.Equal(t, 3, calls)
+	version, err := p.GetDBVersion(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, version)
+
+	t.Run("not_retryable", func(t *testing.T) {
+		var calls int
+		up := &goose.GoFunc{RunTx: func(context.Context, *sql.Tx) error {
+			calls++
+			return sqlStateError("42P01")
+		}}
+		p, err := goose.NewProvider(goose.DialectCockroachDB, db, nil,
+			goose.WithStoreDB(goose.DialectSQLite3, storeDB),
+			goose.WithGoMigrations(goose.NewGoMigration(2, up, nil)),
+			goose.WithDisableGlobalRegistry(true),
+		)
+		require.NoError(t, err)
+		_, err = p.UpTo(ctx, 2)
+		require.Error(t, err)
+		require.Equal(t, 1, calls)
+	})
+}
+
+// sqlStateError is a driver error with a SQLSTATE, like the errors of pgx and lib/pq.
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "SQLSTATE " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func TestProviderClickHouse(t *testing.T) {
+	t.Parallel()
+
+	// The migrations run against SQLite, which is enough to check the variables are substituted.
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "migrated.db"))
+	require.NoError(t, err)
+	storeDB, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "store.db"))
+	require.NoError(t, err)
+	fsys := fstest.MapFS{
+		"1_foo.sql": {Data: []byte(`
+-- +goose ENVSUB ON
+-- +goose Up
+CREATE TABLE ${GOOSE_CLUSTER} (id INTEGER);
+-- +goose Down
+DROP TABLE ${GOOSE_CLUSTER};
+`)},
+	}
+	p, err := goose.NewProvider(goose.DialectClickHouse, db, fsys,
+		goose.WithStoreDB(goose.DialectSQLite3, storeDB),
+		goose.WithClickHouse(database.ClickHouseOptions{Cluster: "events"}),
+	)
+	require.NoError(t, err)
+	_, err = p.Up(context.Background())
+	require.NoError(t, err)
+	var n int
+	err = db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type='table' AND name='events'`).Scan(&n)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	t.Run("invalid", func(t *testing.T) {
+		// Neither the migrations nor the version table use ClickHouse.
+		_, err := goose.NewProvider(goose.DialectSQLite3, db, fsys,
+			goose.WithClickHouse(database.ClickHouseOptions{Cluster: "events"}),
+		)
+		require.Error(t, err)
+		_, err = goose.NewProvider(goose.DialectClickHouse, db, fsys,
+			goose.WithClickHouse(database.ClickHouseOptions{}),
+			goose.WithClickHouse(database.ClickHouseOptions{}),
+		)
+		require.Error(t, err)
+	})
+}
+
+func TestProviderUpgradeTable(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "sql_embed.db"))
+	require.NoError(t, err)
+	// Create a version table with the original layout, as an older goose release did.
+	_, err = db.Exec(`CREATE TABLE goose_db_version (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		version_id INTEGER NOT NULL,
+		is_applied INTEGER NOT NULL,
+		tstamp TIMESTAMP DEFAULT (datetime('now'))
+	);
+	INSERT INTO goose_db_version (version_id, is_applied) VALUES (0, 1);`)
+	require.NoError(t, err)
+	fsys := fstest.MapFS{"1_foo.sql": {Data: []byte(migration1)}}
+	indexExists := func(t *testing.T) bool {
+		t.Helper()
+		var n int
+		err := db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type='index' AND name='goose_db_version_version_id_idx'`).Scan(&n)
+		require.NoError(t, err)
+		return n == 1
+	}
+	ctx := context.Background()
+
+	p, err := goose.NewProvider(goose.DialectSQLite3, db, fsys)
+	require.NoError(t, err)
+	// Applying migrations works with the original layout and does not upgrade it.
+	_, err = p.Up(ctx)
+	require.NoError(t, err)
+	require.False(t, indexExists(t))
+	res, err := p.UpgradeTable(ctx)
+	require.NoError(t, err)
+	require.Equal(t, &database.TableUpgradeResult{From: 1, To: 3}, res)
+	require.True(t, indexExists(t))
+	// Upgrading again is a no-op.
+	res, err = p.UpgradeTable(ctx)
+	require.NoError(t, err)
+	require.Equal(t, &database.TableUpgradeResult{From: 3, To: 3}, res)
+
+	t.Run("no versioning", func(t *testing.T) {
+		p, err := goose.NewProvider(goose.DialectSQLite3, db, fsys, goose.WithDisableVersioning(true))
+		require.NoError(t, err)
+		_, err = p.UpgradeTable(ctx)
+		require.Error(t, err)
+	})
+}
+
+func TestProviderCreateSchema(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "sql_embed.db"))
+	require.NoError(t, err)
+	fsys := fstest.MapFS{
+		"1_foo.sql": {Data: []byte("-- +goose Up\nSELECT 1;\n")},
+	}
+	// SQLite has no schemas.
+	_, err = goose.NewProvider(goose.DialectSQLite3, db, fsys, goose.WithCreateSchema(""))
+	require.ErrorContains(t, err, "schema")
+	// A custom store creates its own version table.
+	store, err := database.NewStore(database.DialectSQLite3, "foo")
+	require.NoError(t, err)
+	_, err = goose.NewProvider("", db, fsys, goose.WithStore(store), goose.WithCreateSchema(""))
+	require.EqualError(t, err, "WithCreateSchema cannot be used with WithStore")
+	// The schema is created on Postgres.
+	_, err = goose.NewProvider(goose.DialectPostgres, db, fsys,
+		goose.WithTableName("migrations.goose_db_version"),
+		goose.WithCreateSchema("app"),
+	)
+	require.NoError(t, err)
+}
+
 func TestPartialErrorUnwrap(t *testing.T) {
 	err := &goose.PartialError{Err: goose.ErrNoCurrentVersion}
 	require.ErrorIs(t, err, goose.ErrNoCurrentVersion)
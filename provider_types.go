@@ -89,3 +89,54 @@ type MigrationStatus struct {
 	State     State
 	AppliedAt time.Time
 }
+
+// HistoryEntry is a single row of the version table, as returned by [Provider.History].
+type HistoryEntry struct {
+	Version int64
+	// IsApplied is false for rows recording a rollback, which the package-level down functions
+	// write instead of deleting the version.
+	IsApplied bool
+	// Timestamp is the time the row was inserted. It is zero if the store does not support listing
+	// the history with timestamps.
+	Timestamp time.Time
+	// Source is the migration with the version, or nil if there is none, such as for version 0 or
+	// migrations removed from the filesystem.
+	Source *Source
+}
+
+// DoctorCheck identifies a check run by [Provider.Doctor].
+type DoctorCheck string
+
+const (
+	// CheckDuplicateVersion reports versions recorded as applied more than once without a
+	// rollback in between.
+	CheckDuplicateVersion DoctorCheck = "duplicate-version"
+	// CheckMissingInitialVersion reports a version table without the version 0 row goose inserts
+	// when it creates the table.
+	CheckMissingInitialVersion DoctorCheck = "missing-initial-version"
+	// CheckFutureTimestamp reports rows recorded later than the current time of the database,
+	// e.g., after its clock was set back. It needs a store that compares timestamps in the
+	// database, see [Provider.Doctor].
+	CheckFutureTimestamp DoctorCheck = "future-timestamp"
+	// CheckMissingSource reports applied versions with no migration source.
+	CheckMissingSource DoctorCheck = "missing-source"
+	// CheckTypeChanged reports applied migrations whose type changed between Go and SQL. It needs
+	// a store that records migration types, see [Provider.Doctor].
+	CheckTypeChanged DoctorCheck = "type-changed"
+	// CheckVersionGap reports gaps in the numbering of sequential (non-timestamp) migrations.
+	CheckVersionGap DoctorCheck = "version-gap"
+	// CheckStaleLock reports a table-based lock that is held past the expiry of its lease.
+	CheckStaleLock DoctorCheck = "stale-lock"
+)
+
+// DoctorFinding is a problem found by [Provider.Doctor].
+type DoctorFinding struct {
+	Check DoctorCheck
+	// Version is the migration version the finding is about, or 0 if it is not about a single
+	// version.
+	Version int64
+	// Message describes the problem.
+	Message string
+	// Remediation suggests how to fix the problem.
+	Remediation string
+}
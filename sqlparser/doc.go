@@ -0,0 +1,23 @@
+// Package sqlparser parses goose SQL migration files.
+//
+// A SQL migration is a plain .sql file with annotations written as SQL comments:
+//
+//	-- +goose Up
+//	CREATE TABLE post (id int);
+//
+//	-- +goose Down
+//	DROP TABLE post;
+//
+// The supported annotations are Up, Down, StatementBegin, StatementEnd, NO TRANSACTION, ENVSUB ON,
+// ENVSUB OFF, DELIMITER and LINT IGNORE. See https://github.com/pressly/goose#sql-migrations for the full file
+// format.
+//
+// Use [Parse] or [ParseFile] to split a migration into its up and down [Statement]s, each
+// annotated with its source line range, whether it was a StatementBegin/StatementEnd block and
+// whether environment variable substitution was applied. These functions are what goose itself
+// uses to read migrations, so tools built on them, such as linters or pre-commit hooks, see the
+// file exactly as goose does.
+//
+// Errors in the file format are reported as a [*ParseError], which records the file path, line and
+// column of the problem.
+package sqlparser
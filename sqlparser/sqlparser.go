@@ -0,0 +1,65 @@
+package sqlparser
+
+import (
+	"io"
+	"io/fs"
+
+	"github.com/pressly/goose/v3/internal/sqlparser"
+)
+
+// Statement is a single SQL statement parsed from a migration file.
+type Statement = sqlparser.Statement
+
+// File is a parsed SQL migration file.
+type File = sqlparser.File
+
+// ParseError is returned when a SQL migration fails to parse. It records where in the file the
+// problem was found so that editors and CI tooling can point at the offending line.
+type ParseError = sqlparser.ParseError
+
+// Direction is the section of a migration file a [Statement] belongs to.
+type Direction = sqlparser.Direction
+
+const (
+	DirectionUp   = sqlparser.DirectionUp
+	DirectionDown = sqlparser.DirectionDown
+)
+
+// ParseOption configures how SQL migrations are parsed.
+type ParseOption = sqlparser.ParseOption
+
+// WithPLSQL parses migrations the way SQL*Plus reads Oracle scripts. A PL/SQL block, such as an
+// anonymous BEGIN or DECLARE block or a CREATE FUNCTION, PROCEDURE, PACKAGE, TRIGGER or TYPE
+// statement, may contain semicolons and is terminated by a slash on a line of its own:
+//
+//	CREATE OR REPLACE PROCEDURE touch_post(p_id NUMBER) AS
+//	BEGIN
+//	  UPDATE post SET updated_at = SYSTIMESTAMP WHERE id = p_id;
+//	END;
+//	/
+//
+// Other statements are terminated by a semicolon, which is removed from the statement since
+// Oracle rejects it, or by a slash. A slash at the end of a StatementBegin block is removed as
+// well. goose parses migrations with this option for the Oracle dialect.
+func WithPLSQL() ParseOption {
+	return sqlparser.WithPLSQL()
+}
+
+// WithEnv adds variables for the ENVSUB annotation. They take precedence over environment
+// variables of the same name. goose uses it to provide GOOSE_ON_CLUSTER to ClickHouse migrations.
+func WithEnv(vars map[string]string) ParseOption {
+	return sqlparser.WithEnv(vars)
+}
+
+// Parse parses a complete SQL migration from r, returning the statements of both directions.
+//
+// Errors in the file format, such as an unknown annotation or an unterminated statement, are
+// reported as a [*ParseError].
+func Parse(r io.Reader, opts ...ParseOption) (*File, error) {
+	return sqlparser.Parse(r, opts...)
+}
+
+// ParseFile parses the SQL migration filename from fsys. See [Parse] for details.
+func ParseFile(fsys fs.FS, filename string, opts ...ParseOption) (*File, error) {
+	return sqlparser.ParseFile(fsys, filename, opts...)
+}
@@ -0,0 +1,76 @@
+package sqlparser_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/pressly/goose/v3/sqlparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFile(t *testing.T) {
+	t.Parallel()
+
+	mapFS := fstest.MapFS{
+		"001_foo.sql": mapFile(`-- +goose NO TRANSACTION
+-- +goose Up
+CREATE TABLE foo (
+  id int
+);
+
+-- +goose ENVSUB ON
+-- +goose StatementBegin
+SELECT '${GOOSE_SQLPARSER_UNSET_VAR-default}';
+SELECT 2;
+-- +goose StatementEnd
+-- +goose ENVSUB OFF
+
+-- +goose Down
+DROP TABLE foo;
+`),
+	}
+	f, err := sqlparser.ParseFile(mapFS, "001_foo.sql")
+	require.NoError(t, err)
+	require.Equal(t, "001_foo.sql", f.Path)
+	require.False(t, f.UseTx)
+	require.Len(t, f.Up, 2)
+	require.Len(t, f.Down, 1)
+
+	require.Equal(t, &sqlparser.Statement{
+		SQL:       "CREATE TABLE foo (\n  id int\n);",
+		Direction: sqlparser.DirectionUp,
+		StartLine: 3,
+		EndLine:   5,
+	}, f.Up[0])
+	require.Equal(t, &sqlparser.Statement{
+		SQL:       "SELECT 'default';\nSELECT 2;",
+		Direction: sqlparser.DirectionUp,
+		StartLine: 9,
+		EndLine:   10,
+		Envsub:    true,
+		Block:     true,
+	}, f.Up[1])
+	require.Equal(t, &sqlparser.Statement{
+		SQL:       "DROP TABLE foo;",
+		Direction: sqlparser.DirectionDown,
+		StartLine: 15,
+		EndLine:   15,
+	}, f.Down[0])
+
+	t.Run("parse_error", func(t *testing.T) {
+		mapFS := fstest.MapFS{
+			"002_bar.sql": mapFile("-- +goose Up\nSELECT 1\n"),
+		}
+		_, err := sqlparser.ParseFile(mapFS, "002_bar.sql")
+		var parseErr *sqlparser.ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, "002_bar.sql", parseErr.Path)
+		require.Equal(t, 2, parseErr.Line)
+	})
+}
+
+func mapFile(data string) *fstest.MapFile {
+	return &fstest.MapFile{
+		Data: []byte(data),
+	}
+}